@@ -5,8 +5,6 @@ import (
 
 	"github.com/ABHINAV-SUREKA/gotracing/tracing"
 	log "github.com/sirupsen/logrus"
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/propagation"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
@@ -23,13 +21,17 @@ func main() {
 	})
 
 	if err != nil {
-		log.Errorf("Could not create Tracer Provider: %s", err)
+		log.Fatalf("Could not create Tracer Provider: %s", err)
 	}
 
-	otel.SetTracerProvider(manager.TracerProvider)
-
-	/* Traces can extend beyond a single process.
+	/* Set the global tracer provider & propagator (Eg: used by instrumentation libraries).
+	Traces can extend beyond a single process.
 	This requires context propagation of identifiers for a trace to remote processes over the wire.
 	*/
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(manager.Propagator, propagation.Baggage{}))
+	manager.InstallGlobals()
+
+	// Flush remaining spans before exiting
+	if err := manager.Shutdown(context.Background()); err != nil {
+		log.Errorf("Could not shutdown Tracer Provider: %s", err)
+	}
 }
@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// baggageProcessor copies baggage members (Eg: user/tenant IDs propagated by upstream services) from the context
+// of every span being started onto the span, as attributes of the same name.
+type baggageProcessor struct {
+	keys []string
+}
+
+var _ sdktrace.SpanProcessor = baggageProcessor{}
+
+// newBaggageProcessor creates a span processor copying the baggage members named keys onto every span.
+func newBaggageProcessor(keys []string) baggageProcessor {
+	return baggageProcessor{keys: keys}
+}
+
+func (p baggageProcessor) OnStart(ctx context.Context, span sdktrace.ReadWriteSpan) {
+	b := baggage.FromContext(ctx)
+	if b.Len() == 0 {
+		return
+	}
+	attrs := make([]attribute.KeyValue, 0, len(p.keys))
+	for _, key := range p.keys {
+		if member := b.Member(key); member.Key() != "" {
+			attrs = append(attrs, attribute.String(key, member.Value()))
+		}
+	}
+	span.SetAttributes(attrs...)
+}
+
+func (baggageProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (baggageProcessor) Shutdown(context.Context) error { return nil }
+
+func (baggageProcessor) ForceFlush(context.Context) error { return nil }
+
+// SetBaggageMember returns a copy of ctx whose baggage has the member key set to value (replacing any member of the
+// same key), so that it's propagated to remote processes along with the trace context (see Manager.Inject).
+// value may contain any characters, it is percent-encoded on the wire. It fails if key isn't a valid baggage key.
+// Eg: ctx, err := m.SetBaggageMember(ctx, "tenant.id", tenantID)
+func (m *Manager) SetBaggageMember(ctx context.Context, key, value string) (context.Context, error) {
+	member, err := baggage.NewMemberRaw(key, value)
+	if err != nil {
+		return ctx, fmt.Errorf("invalid baggage member %q: %s", key, err)
+	}
+	b, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx, fmt.Errorf("could not set baggage member %q: %s", key, err)
+	}
+	return baggage.ContextWithBaggage(ctx, b), nil
+}
+
+// BaggageMember returns the value of the baggage member key in ctx, or "" if there is none.
+func (m *Manager) BaggageMember(ctx context.Context, key string) string {
+	return baggage.FromContext(ctx).Member(key).Value()
+}
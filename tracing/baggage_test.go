@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestCopyBaggageKeys(t *testing.T) {
+	ctx := context.Background()
+	m, err := NewInMemory(ctx, Config{Logger: NopLogger, CopyBaggageKeys: []string{"tenant.id", "user.id"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Shutdown(ctx)
+
+	baggageCtx, err := m.SetBaggageMember(ctx, "tenant.id", "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Not in CopyBaggageKeys
+	if baggageCtx, err = m.SetBaggageMember(baggageCtx, "session.id", "42"); err != nil {
+		t.Fatal(err)
+	}
+	_, span := m.Start(baggageCtx, "operation")
+	span.End()
+
+	spans := m.RecordedSpans(ctx)
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	attrs := attribute.NewSet(spans[0].Attributes()...)
+	if value, ok := attrs.Value("tenant.id"); !ok || value.AsString() != "acme" {
+		t.Errorf("tenant.id attribute = %q (set: %t), want acme", value.AsString(), ok)
+	}
+	for _, key := range []attribute.Key{"user.id", "session.id"} {
+		if attrs.HasValue(key) {
+			t.Errorf("%s attribute set, want only the baggage members in CopyBaggageKeys", key)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+)
+
+// Clone creates a new, independent Manager (with its own TracerProvider, processors & exporters) from the Config this
+// manager was created with, with the non-zero fields of overrides set on top of it. Fields left at their zero value in
+// overrides inherit this manager's settings; as a consequence, boolean fields can only be overridden to true
+// (Eg: a clone of a manager with Insecure or AlwaysSampleErrors set can't unset them: use New for such a manager).
+// Eg: a dedicated high-fidelity stream for errors: m.Clone(ctx, Config{Endpoint: "errors-collector:4317", Sampler: sdktrace.AlwaysSample()})
+//
+// Since span processors & exporters can't be shared between TracerProviders, SpanProcessors and AdditionalExporters
+// aren't inherited, and neither is SetGlobal (so that the clone doesn't replace this manager as the otel globals).
+// For the same reason, a clone writing traces to a file this manager writes to (see BackendFile) is an error:
+// override FileOutput (and Exporters, if they write to a file). The clone must be shut down separately.
+func (m *Manager) Clone(ctx context.Context, overrides Config) (*Manager, error) {
+	cfg := m.config
+	cfg.SpanProcessors = nil
+	cfg.AdditionalExporters = nil
+	cfg.SetGlobal = false
+	merged := mergeConfig(cfg, overrides)
+	exporterConfigs := append([]ExporterConfig{merged.exporterConfig()}, merged.Exporters...)
+	if path := sharedFileOutput(m.exporterConfigs, exporterConfigs); path != "" && !merged.Disabled {
+		return nil, fmt.Errorf("could not clone manager: the clone would write traces to %s as well, set another FileOutput", path)
+	}
+	return New(ctx, merged)
+}
+
+// sharedFileOutput returns the trace file both a and b exporters write to (see BackendFile), or "" if there's none.
+// Two managers writing to (and rotating) the same file would corrupt it.
+func sharedFileOutput(a, b []ExporterConfig) string {
+	paths := map[string]bool{}
+	for _, cfg := range a {
+		if cfg.backend() == BackendFile {
+			paths[absPath(cfg.FileOutput)] = true
+		}
+	}
+	for _, cfg := range b {
+		if cfg.backend() == BackendFile && paths[absPath(cfg.FileOutput)] {
+			return cfg.FileOutput
+		}
+	}
+	return ""
+}
+
+// absPath returns the absolute path of path, or path itself if it can't be determined.
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// mergeConfig returns base with the non-zero fields of overrides set on it.
+func mergeConfig(base, overrides Config) Config {
+	merged := reflect.ValueOf(&base).Elem()
+	fields := reflect.ValueOf(overrides)
+	for i := 0; i < fields.NumField(); i++ {
+		if field := fields.Field(i); !field.IsZero() {
+			merged.Field(i).Set(field)
+		}
+	}
+	return base
+}
@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// k8sNamespaceFile - the file Kubernetes mounts into every Pod containing the Pod's namespace.
+const k8sNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// k8sDetector detects Kubernetes resource attributes (k8s.pod.name, k8s.namespace.name, k8s.node.name) of the Pod
+// it's running in. The conventional downward API environment variables (POD_NAME, POD_NAMESPACE, NODE_NAME), if set,
+// take precedence over what is detected from the Pod's hostname and service account.
+// It detects nothing when not running inside a Pod.
+type k8sDetector struct{}
+
+func (k8sDetector) Detect(context.Context) (*resource.Resource, error) {
+	// Kubernetes sets KUBERNETES_SERVICE_HOST in every container
+	if _, ok := os.LookupEnv("KUBERNETES_SERVICE_HOST"); !ok {
+		return resource.Empty(), nil
+	}
+
+	var attrs []attribute.KeyValue
+	// The Pod's hostname is its name (unless overridden in the Pod spec)
+	if podName := os.Getenv("POD_NAME"); podName != "" {
+		attrs = append(attrs, attribute.String("k8s.pod.name", podName))
+	} else if podName, err := os.Hostname(); err == nil {
+		attrs = append(attrs, attribute.String("k8s.pod.name", podName))
+	}
+	if namespace := os.Getenv("POD_NAMESPACE"); namespace != "" {
+		attrs = append(attrs, attribute.String("k8s.namespace.name", namespace))
+	} else if namespace, err := os.ReadFile(k8sNamespaceFile); err == nil {
+		attrs = append(attrs, attribute.String("k8s.namespace.name", strings.TrimSpace(string(namespace))))
+	}
+	// The node name is only available through the downward API
+	if nodeName := os.Getenv("NODE_NAME"); nodeName != "" {
+		attrs = append(attrs, attribute.String("k8s.node.name", nodeName))
+	}
+	return resource.NewSchemaless(attrs...), nil
+}
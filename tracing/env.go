@@ -0,0 +1,187 @@
+package tracing
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ConfigFromEnv returns a Config populated from the standard OpenTelemetry environment variables:
+//
+//	OTEL_SDK_DISABLED            Eg: true
+//	OTEL_EXPORTER_OTLP_ENDPOINT  Eg: https://collector:4317
+//	OTEL_EXPORTER_OTLP_PROTOCOL  Eg: grpc, http/protobuf
+//	OTEL_EXPORTER_OTLP_HEADERS   Eg: authorization=Bearer%20token,tenant=team-a
+//	OTEL_SERVICE_NAME            Eg: my-service
+//	OTEL_RESOURCE_ATTRIBUTES     Eg: service.namespace=infra,deployment.environment=prod
+//	OTEL_TRACES_SAMPLER          Eg: parentbased_traceidratio
+//	OTEL_TRACES_SAMPLER_ARG      Eg: 0.25
+//
+// See https://opentelemetry.io/docs/specs/otel/configuration/sdk-environment-variables/
+//
+// Variables that aren't set leave the corresponding fields at their zero value; invalid values are logged
+// (to the global logrus logger) and ignored. Fields can be overridden on the returned Config before passing it to New.
+func ConfigFromEnv() Config {
+	return configFromEnv(Config{}.logger())
+}
+
+// configFromEnv is ConfigFromEnv, logging invalid values to logger.
+func configFromEnv(logger Logger) Config {
+	var cfg Config
+
+	// Lets operators turn tracing off without redeploying (Eg: by changing the Pod's environment)
+	if disabled, ok := os.LookupEnv("OTEL_SDK_DISABLED"); ok {
+		cfg.Disabled = strings.EqualFold(strings.TrimSpace(disabled), "true")
+	}
+
+	if protocol, ok := os.LookupEnv("OTEL_EXPORTER_OTLP_PROTOCOL"); ok && protocol != "" {
+		switch protocol {
+		case "grpc":
+			cfg.Transport = TransportGRPC
+		case "http/protobuf":
+			cfg.Transport = TransportHTTP
+		default:
+			logger.Warnf("Ignoring unsupported OTEL_EXPORTER_OTLP_PROTOCOL: %q", protocol)
+		}
+	}
+
+	if endpoint, ok := os.LookupEnv("OTEL_EXPORTER_OTLP_ENDPOINT"); ok && endpoint != "" {
+		// The endpoint is a URL (Eg: http://localhost:4317), whereas Config.Endpoint is host:port[/path]
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Host == "" {
+			logger.Warnf("Ignoring invalid OTEL_EXPORTER_OTLP_ENDPOINT: %q", endpoint)
+		} else {
+			cfg.Endpoint = u.Host
+			if u.Port() == "" {
+				// Config.Endpoint requires a port: use the URL's (implied) one, Eg: https://collector -> collector:443
+				cfg.Endpoint = net.JoinHostPort(u.Hostname(), defaultURLPort(u.Scheme, cfg.Transport))
+			}
+			cfg.Insecure = u.Scheme == "http"
+			// For OTLP/HTTP, the endpoint is a base URL to which the signal-specific path is appended
+			if path := strings.Trim(u.Path, "/"); cfg.Transport == TransportHTTP && path != "" {
+				cfg.Endpoint += "/" + path + "/v1/traces"
+			}
+		}
+	}
+
+	if headers, ok := os.LookupEnv("OTEL_EXPORTER_OTLP_HEADERS"); ok {
+		cfg.Headers = parseKeyValueList("OTEL_EXPORTER_OTLP_HEADERS", headers, logger)
+	}
+
+	if attributes, ok := os.LookupEnv("OTEL_RESOURCE_ATTRIBUTES"); ok {
+		cfg.Attributes = parseKeyValueList("OTEL_RESOURCE_ATTRIBUTES", attributes, logger)
+	}
+	// OTEL_SERVICE_NAME takes precedence over a service.name set in OTEL_RESOURCE_ATTRIBUTES
+	if serviceName, ok := os.LookupEnv("OTEL_SERVICE_NAME"); ok && serviceName != "" {
+		if cfg.Attributes == nil {
+			cfg.Attributes = make(map[string]string, 1)
+		}
+		cfg.Attributes[serviceNameKey] = serviceName
+	}
+
+	if sampler, ok := os.LookupEnv("OTEL_TRACES_SAMPLER"); ok && sampler != "" {
+		cfg.Sampler = samplerFromEnv(sampler, os.Getenv("OTEL_TRACES_SAMPLER_ARG"), logger)
+	}
+
+	return cfg
+}
+
+// withEnvFallback returns a copy of c with the fields left at their zero value filled from the
+// standard OpenTelemetry environment variables (see ConfigFromEnv).
+func (c Config) withEnvFallback() Config {
+	env := configFromEnv(c.logger())
+	if !c.Disabled {
+		c.Disabled = env.Disabled
+	}
+	if c.Endpoint == "" && env.Endpoint != "" {
+		c.Endpoint = env.Endpoint
+		c.Insecure = c.Insecure || env.Insecure
+	}
+	if c.Transport == TransportGRPC {
+		c.Transport = env.Transport
+	}
+	if c.Headers == nil {
+		c.Headers = env.Headers
+	}
+	if c.Attributes == nil {
+		c.Attributes = env.Attributes
+	}
+	if c.Sampler == nil && c.SampleRatio == 0 {
+		c.Sampler = env.Sampler
+	}
+	return c
+}
+
+// defaultURLPort returns the port implied by an OTEL_EXPORTER_OTLP_ENDPOINT URL without one: the default port of
+// its scheme, or the OTLP default port of transport (4317 for gRPC, 4318 for HTTP) for other schemes.
+func defaultURLPort(scheme string, transport Transport) string {
+	switch {
+	case scheme == "https":
+		return "443"
+	case scheme == "http":
+		return "80"
+	case transport == TransportHTTP:
+		return "4318"
+	default:
+		return "4317"
+	}
+}
+
+// parseKeyValueList parses a comma-separated list of (percent-encoded) key=value pairs as defined by the OTel spec.
+// Eg: "key1=value1,key2=value%202"
+func parseKeyValueList(name, list string, logger Logger) map[string]string {
+	values := make(map[string]string)
+	for _, pair := range strings.Split(list, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			logger.Warnf("Ignoring invalid entry in %s: %q", name, pair)
+			continue
+		}
+		value, err := url.PathUnescape(strings.TrimSpace(v))
+		if err != nil {
+			logger.Warnf("Ignoring invalid entry in %s: %q: %s", name, pair, err)
+			continue
+		}
+		values[k] = value
+	}
+	return values
+}
+
+// samplerFromEnv returns the sampler described by OTEL_TRACES_SAMPLER & OTEL_TRACES_SAMPLER_ARG, or nil if invalid.
+func samplerFromEnv(sampler, arg string, logger Logger) sdktrace.Sampler {
+	ratio := 1.0
+	if arg != "" && strings.HasSuffix(sampler, "traceidratio") {
+		var err error
+		ratio, err = strconv.ParseFloat(arg, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			logger.Warnf("Ignoring invalid OTEL_TRACES_SAMPLER_ARG %q, using 1.0", arg)
+			ratio = 1.0
+		}
+	}
+
+	switch sampler {
+	case "always_on":
+		return sdktrace.AlwaysSample()
+	case "always_off":
+		return sdktrace.NeverSample()
+	case "traceidratio":
+		return sdktrace.TraceIDRatioBased(ratio)
+	case "parentbased_always_on":
+		return sdktrace.ParentBased(sdktrace.AlwaysSample())
+	case "parentbased_always_off":
+		return sdktrace.ParentBased(sdktrace.NeverSample())
+	case "parentbased_traceidratio":
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))
+	default:
+		logger.Warnf("Ignoring unsupported OTEL_TRACES_SAMPLER: %q", sampler)
+		return nil
+	}
+}
@@ -0,0 +1,62 @@
+package tracing
+
+import "testing"
+
+func TestConfigFromEnvEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint     string
+		protocol     string
+		wantEndpoint string
+		wantInsecure bool
+	}{
+		{endpoint: "https://collector:4317", wantEndpoint: "collector:4317"},
+		{endpoint: "http://collector:4317", wantEndpoint: "collector:4317", wantInsecure: true},
+		// The port implied by the scheme
+		{endpoint: "https://collector", wantEndpoint: "collector:443"},
+		{endpoint: "http://collector", wantEndpoint: "collector:80", wantInsecure: true},
+		{endpoint: "https://[::1]", wantEndpoint: "[::1]:443"},
+		{endpoint: "https://collector", protocol: "http/protobuf", wantEndpoint: "collector:443"},
+		{endpoint: "http://collector:4318/otlp", protocol: "http/protobuf", wantEndpoint: "collector:4318/otlp/v1/traces", wantInsecure: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.endpoint+" "+tt.protocol, func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", tt.endpoint)
+			t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", tt.protocol)
+
+			cfg := configFromEnv(NopLogger)
+			if cfg.Endpoint != tt.wantEndpoint || cfg.Insecure != tt.wantInsecure {
+				t.Errorf("Endpoint, Insecure = %q, %t, want %q, %t", cfg.Endpoint, cfg.Insecure, tt.wantEndpoint, tt.wantInsecure)
+			}
+			// The endpoint must be accepted by New
+			cfg.Logger = NopLogger
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("Validate() = %s", err)
+			}
+		})
+	}
+}
+
+// warningCountingLogger counts the warnings logged, discarding all the logs.
+type warningCountingLogger struct {
+	nopLogger
+	warnings int
+}
+
+func (l *warningCountingLogger) Warnf(string, ...interface{}) {
+	l.warnings++
+}
+
+func TestConfigFromEnvIgnoresEmptyVariables(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "")
+	t.Setenv("OTEL_TRACES_SAMPLER", "")
+
+	logger := &warningCountingLogger{}
+	cfg := configFromEnv(logger)
+	if logger.warnings != 0 {
+		t.Errorf("logged %d warnings, want 0", logger.warnings)
+	}
+	if cfg.Endpoint != "" || cfg.Transport != TransportGRPC || cfg.Sampler != nil {
+		t.Errorf("Endpoint, Transport, Sampler = %q, %d, %v, want the zero values", cfg.Endpoint, cfg.Transport, cfg.Sampler)
+	}
+}
@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AlwaysSampleOnError returns a sampler following the decisions of base, except that the spans recording an error
+// (i.e. with an exception event or an Error status, Eg: see Manager.RecordError) are always exported.
+// Whether a span records an error is only known once it ends, while samplers decide when it starts: the spans dropped
+// by base are thus recorded (sdktrace.RecordOnly) instead, and the manager's processors export those that ended with
+// an error (as if they were sampled). Note: recording every span has a CPU & memory cost, even if most aren't exported.
+// Also, the errored spans are exported on their own: their parent & children are only exported if sampled.
+// Eg: Config{Sampler: AlwaysSampleOnError(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(0.01)))}
+// See also Config.AlwaysSampleErrors.
+func AlwaysSampleOnError(base sdktrace.Sampler) sdktrace.Sampler {
+	return &errorSampler{base: base}
+}
+
+// errorSampler records the spans dropped by base, so that errored ones can be exported (see errorSamplingProcessor).
+type errorSampler struct {
+	base sdktrace.Sampler
+}
+
+func (s *errorSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.base.ShouldSample(p)
+	if result.Decision == sdktrace.Drop {
+		result.Decision = sdktrace.RecordOnly
+	}
+	return result
+}
+
+func (s *errorSampler) Description() string {
+	return fmt.Sprintf("AlwaysSampleOnError{%s}", s.base.Description())
+}
+
+// sampleErrors returns whether c asks for the errored spans to be exported, see AlwaysSampleOnError.
+func (c Config) sampleErrors() bool {
+	_, ok := c.Sampler.(*errorSampler)
+	return c.AlwaysSampleErrors || ok
+}
+
+// errorSamplingProcessor hands over to next the sampled spans, and the recorded (but not sampled) spans
+// that ended with an error, marked as sampled.
+type errorSamplingProcessor struct {
+	next sdktrace.SpanProcessor
+}
+
+func newErrorSamplingProcessor(next sdktrace.SpanProcessor) *errorSamplingProcessor {
+	return &errorSamplingProcessor{next: next}
+}
+
+func (p *errorSamplingProcessor) OnStart(ctx context.Context, span sdktrace.ReadWriteSpan) {
+	p.next.OnStart(ctx, span)
+}
+
+func (p *errorSamplingProcessor) OnEnd(span sdktrace.ReadOnlySpan) {
+	if span.SpanContext().IsSampled() {
+		p.next.OnEnd(span)
+	} else if hasError(span) {
+		p.next.OnEnd(sampledSpan{span})
+	}
+}
+
+func (p *errorSamplingProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *errorSamplingProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// hasError returns whether span recorded an error.
+func hasError(span sdktrace.ReadOnlySpan) bool {
+	if span.Status().Code == codes.Error {
+		return true
+	}
+	for _, event := range span.Events() {
+		if event.Name == semconv.ExceptionEventName {
+			return true
+		}
+	}
+	return false
+}
+
+// sampledSpan is a span whose span context is marked as sampled, so that span processors (& exporters) export it.
+type sampledSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s sampledSpan) SpanContext() trace.SpanContext {
+	spanContext := s.ReadOnlySpan.SpanContext()
+	return spanContext.WithTraceFlags(spanContext.TraceFlags().WithSampled(true))
+}
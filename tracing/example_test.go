@@ -0,0 +1,38 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ABHINAV-SUREKA/gotracing/tracing"
+)
+
+func ExampleManager_Span() {
+	ctx := context.Background()
+	manager, err := tracing.NewInMemory(ctx, tracing.Config{Logger: tracing.NopLogger})
+	if err != nil {
+		panic(err)
+	}
+	defer manager.Shutdown(ctx)
+
+	// The span ends when the function returns, recording the error it returns, if any
+	process := func(ctx context.Context, orderID string) (err error) {
+		ctx, end := manager.Span(ctx, "process-order")
+		defer end(&err)
+
+		if orderID == "" {
+			return errors.New("missing order ID")
+		}
+		return nil
+	}
+
+	_ = process(ctx, "42")
+	_ = process(ctx, "")
+	for _, span := range manager.RecordedSpans(ctx) {
+		fmt.Printf("%s: %s %q\n", span.Name(), span.Status().Code, span.Status().Description)
+	}
+	// Output:
+	// process-order: Unset ""
+	// process-order: Error "missing order ID"
+}
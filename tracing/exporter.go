@@ -0,0 +1,439 @@
+package tracing
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/jaeger"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// Backend - the kind of system traces are exported to.
+type Backend int
+
+const (
+	// BackendOTLP sends traces to an OpenTelemetry collector/remote backend/etc. using OTLP (default).
+	BackendOTLP Backend = iota
+	// BackendZipkin sends traces to a Zipkin collector.
+	BackendZipkin
+	// BackendStdout writes traces to std output (or Config.DebugOutput).
+	BackendStdout
+	// BackendJaeger sends traces to a (legacy) Jaeger agent using Thrift compact over UDP.
+	// Note: recent Jaeger versions accept OTLP directly, in which case BackendOTLP should be preferred.
+	BackendJaeger
+	// BackendInMemory keeps exported spans in memory, accessible via Manager.RecordedSpans (or Manager.InMemoryExporter).
+	// See NewInMemory. Useful for unit testing tracing code without starting any external process.
+	BackendInMemory
+	// BackendFile writes traces to the file Config.FileOutput in the OTLP JSON file format, Eg: in air-gapped environments.
+	BackendFile
+)
+
+// Transport - the protocol used to send traces to the collector/remote backend/etc.
+type Transport int
+
+const (
+	// TransportGRPC sends traces using OTLP over gRPC (default).
+	TransportGRPC Transport = iota
+	// TransportHTTP sends traces using OTLP over HTTP/1.1 with protobuf encoding.
+	// Useful where gRPC can't be used. Eg: serverless environments, strict firewalls, etc.
+	TransportHTTP
+)
+
+// Compression - the compression applied to OTLP export requests.
+type Compression string
+
+const (
+	// CompressionNone sends export requests uncompressed (default).
+	CompressionNone Compression = "none"
+	// CompressionGzip compresses export requests using gzip.
+	CompressionGzip Compression = "gzip"
+)
+
+// DebugFormat - the format of the debug output (see Config.DebugOutput).
+type DebugFormat int
+
+const (
+	// DebugFormatPretty writes each span as indented, human-readable JSON (default).
+	DebugFormatPretty DebugFormat = iota
+	// DebugFormatJSON writes each span as compact JSON on a single line (JSON lines),
+	// Eg: for log aggregation pipelines (Eg: Datadog log-to-trace) or to pipe into jq.
+	DebugFormatJSON
+)
+
+// ExporterConfig describes a single exporter to send traces to.
+// The fields behave the same as the fields of the same name on Config.
+type ExporterConfig struct {
+	Endpoint          string
+	Backend           Backend
+	Transport         Transport
+	Insecure          bool
+	TLSClientCertFile string
+	TLSClientKeyFile  string
+	TLSCACertFile     string
+	TLSConfig         *tls.Config
+	Headers           map[string]string
+	GRPCDialOptions   []grpc.DialOption
+	GRPCConn          *grpc.ClientConn
+	Compression       Compression
+	ExportTimeout     time.Duration
+	RetryConfig       *RetryConfig
+	DebugOutput       io.Writer
+	DebugFormat       DebugFormat
+	FileOutput        string
+	FileTruncate      bool
+	FileMaxMB         int
+	FileMaxBackups    int
+}
+
+// RetryConfig - retry policy (exponential backoff) for export requests failing with a transient error.
+// Eg: while the collector restarts.
+type RetryConfig struct {
+	// Whether to retry failed export requests
+	Enabled bool
+
+	// Time to wait after the first failure before retrying.
+	// If unset, defaults to 5s
+	InitialInterval time.Duration
+
+	// Upper bound on the time to wait between consecutive retries.
+	// If unset, defaults to 30s
+	MaxInterval time.Duration
+
+	// Max total time spent retrying an export request, after which its spans are dropped.
+	// If unset, defaults to 1m
+	MaxElapsedTime time.Duration
+
+	// Max number of attempts for an export request (including the first one), after which its spans are dropped.
+	// Only applies to trace exports: log & metric exports (see Config.EnableLogs, Config.EnableMetrics) are only
+	// bounded by MaxElapsedTime. If unset, attempts are only bounded by MaxElapsedTime.
+	MaxAttempts int
+}
+
+// withDefaults returns a copy of c with unset intervals set to the OTLP SDK defaults.
+func (c RetryConfig) withDefaults() RetryConfig {
+	if c.InitialInterval <= 0 {
+		c.InitialInterval = 5 * time.Second
+	}
+	if c.MaxInterval <= 0 {
+		c.MaxInterval = 30 * time.Second
+	}
+	if c.MaxElapsedTime <= 0 {
+		c.MaxElapsedTime = time.Minute
+	}
+	return c
+}
+
+// backend returns the backend c effectively exports to: DebugOutput, if set, takes precedence over FileOutput,
+// which takes precedence over Backend.
+func (c ExporterConfig) backend() Backend {
+	switch {
+	case c.DebugOutput != nil:
+		return BackendStdout
+	case c.FileOutput != "":
+		return BackendFile
+	}
+	return c.Backend
+}
+
+// resolvedEndpoint returns the endpoint the exporter described by c sends traces to (see Manager.Endpoint).
+func (c ExporterConfig) resolvedEndpoint() string {
+	switch c.backend() {
+	case BackendStdout:
+		return EndpointStdout
+	case BackendInMemory:
+		return EndpointInMemory
+	case BackendFile:
+		return c.FileOutput
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = defaultEndpoint(c)
+	}
+	if c.backend() == BackendOTLP {
+		// Malformed endpoints are rejected by New
+		if normalized, err := normalizeOTLPEndpoint(c); err == nil {
+			return normalized.Endpoint
+		}
+	}
+	return c.Endpoint
+}
+
+// defaultEndpoint returns the endpoint to send traces to when cfg.Endpoint is empty.
+func defaultEndpoint(cfg ExporterConfig) string {
+	switch {
+	case cfg.Backend == BackendZipkin:
+		return ZipkinTracingEndpoint
+	case cfg.Backend == BackendJaeger:
+		return JaegerTracingEndpoint
+	case cfg.Transport == TransportHTTP:
+		return HTTPTracingEndpoint
+	default:
+		return GRPCTracingEndpoint
+	}
+}
+
+// normalizeOTLPEndpoint validates the OTLP endpoint of cfg, so that a malformed endpoint (Eg: copy-pasted with a scheme
+// from the OTLP docs) fails with a clear error naming it instead of failing opaquely on the first export.
+// With TransportGRPC, the endpoint must be host:port. With TransportHTTP, it may also be a URL
+// (Eg: http://localhost:4318/v1/traces), which is converted to host:port/path, the "http" scheme implying Insecure.
+func normalizeOTLPEndpoint(cfg ExporterConfig) (ExporterConfig, error) {
+	if cfg.Transport == TransportHTTP && strings.Contains(cfg.Endpoint, "://") {
+		u, err := url.Parse(cfg.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return cfg, fmt.Errorf("invalid endpoint %q: must be host:port[/path] or an http(s) URL (Eg: localhost:4318/v1/traces)", cfg.Endpoint)
+		}
+		// The port may be omitted from a URL, in which case the scheme's default port is used
+		cfg.Endpoint = u.Host + strings.TrimSuffix(u.Path, "/")
+		cfg.Insecure = cfg.Insecure || u.Scheme == "http"
+		return cfg, nil
+	}
+
+	hostPort := cfg.Endpoint
+	if cfg.Transport == TransportHTTP {
+		hostPort, _, _ = strings.Cut(cfg.Endpoint, "/")
+	}
+	if _, port, err := net.SplitHostPort(hostPort); err != nil || port == "" || strings.Contains(hostPort, "/") {
+		if cfg.Transport == TransportHTTP {
+			return cfg, fmt.Errorf("invalid endpoint %q: must be host:port[/path] or an http(s) URL (Eg: localhost:4318/v1/traces)", cfg.Endpoint)
+		}
+		return cfg, fmt.Errorf("invalid endpoint %q: must be host:port without a scheme (Eg: localhost:4317)", cfg.Endpoint)
+	}
+	return cfg, nil
+}
+
+// newExporter creates the span exporter described by cfg.
+// ctx bounds the exporter's startup (Eg: connecting to the collector, depending on the exporter).
+// logger receives the logs of the exporter wrappers (Eg: retries giving up).
+func newExporter(ctx context.Context, cfg ExporterConfig, logger Logger) (sdktrace.SpanExporter, error) {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = defaultEndpoint(cfg)
+	}
+	if cfg.Compression == "" {
+		cfg.Compression = CompressionNone
+	}
+
+	backend := cfg.backend()
+	switch backend {
+	case BackendOTLP:
+		if cfg.GRPCConn == nil || cfg.Transport != TransportGRPC {
+			var err error
+			if cfg, err = normalizeOTLPEndpoint(cfg); err != nil {
+				return nil, err
+			}
+		}
+		tlsConfig, err := newTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		var traceClient otlptrace.Client
+		switch cfg.Transport {
+		case TransportGRPC:
+			// cfg.GRPCConn is either the caller's connection or the one dialed by New (see dialExporterConn)
+			traceClient = newGRPCClient(cfg, tlsConfig)
+		case TransportHTTP:
+			traceClient = newHTTPClient(cfg, tlsConfig)
+		default:
+			return nil, fmt.Errorf("unsupported transport: %d", cfg.Transport)
+		}
+		exporter, err := otlptrace.New(ctx, traceClient)
+		if err != nil {
+			return nil, err
+		}
+		return withRetry(exporter, cfg.RetryConfig, logger), nil
+	case BackendZipkin:
+		var options []zipkin.Option
+		if cfg.ExportTimeout > 0 {
+			options = append(options, zipkin.WithClient(&http.Client{Timeout: cfg.ExportTimeout}))
+		}
+		exporter, err := zipkin.New(cfg.Endpoint, options...)
+		if err != nil {
+			return nil, err
+		}
+		return withRetry(exporter, cfg.RetryConfig, logger), nil
+	case BackendJaeger:
+		host, port, err := net.SplitHostPort(cfg.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Jaeger agent endpoint %q: %s", cfg.Endpoint, err)
+		}
+		exporter, err := jaeger.New(jaeger.WithAgentEndpoint(jaeger.WithAgentHost(host), jaeger.WithAgentPort(port)))
+		if err != nil {
+			return nil, err
+		}
+		return withRetry(exporter, cfg.RetryConfig, logger), nil
+	case BackendInMemory:
+		return tracetest.NewInMemoryExporter(), nil
+	case BackendFile:
+		// The file is opened by otlptrace.New (starting the client), and closed when the exporter is shut down
+		return otlptrace.New(ctx, newFileClient(cfg.FileOutput, cfg.FileTruncate, cfg.FileMaxMB, cfg.FileMaxBackups))
+	case BackendStdout:
+		output := cfg.DebugOutput
+		if output == nil {
+			output = os.Stdout
+		}
+		options := []stdouttrace.Option{stdouttrace.WithWriter(output)}
+		if cfg.DebugFormat == DebugFormatPretty {
+			options = append(options, stdouttrace.WithPrettyPrint())
+		}
+		return stdouttrace.New(options...)
+	default:
+		return nil, fmt.Errorf("unsupported backend: %d", backend)
+	}
+}
+
+// withRetry wraps exporter so that failed exports are retried, if enabled by config.
+// The native retries of the OTLP exporters are disabled when config is set, so that MaxAttempts is honoured.
+func withRetry(exporter sdktrace.SpanExporter, config *RetryConfig, logger Logger) sdktrace.SpanExporter {
+	if config == nil || !config.Enabled {
+		return exporter
+	}
+	return newRetryExporter(exporter, *config, logger)
+}
+
+// dialExporterConn dials the gRPC connection of the OTLP/gRPC exporter described by cfg, within ctx.
+// It returns nil if the exporter doesn't need one: another backend/transport, or cfg.GRPCConn is set.
+// The exporter's own connection would otherwise be created lazily (ignoring grpc.WithBlock()), and couldn't be
+// shared by the log & metric exporters or checked by Manager.Healthy. The caller must close the connection.
+func dialExporterConn(ctx context.Context, cfg ExporterConfig) (*grpc.ClientConn, error) {
+	if cfg.backend() != BackendOTLP || cfg.Transport != TransportGRPC || cfg.GRPCConn != nil {
+		return nil, nil
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = defaultEndpoint(cfg)
+	}
+	cfg, err := normalizeOTLPEndpoint(cfg)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return dialGRPC(ctx, cfg, tlsConfig)
+}
+
+// dialGRPC dials the gRPC connection to the collector described by cfg, with the transport security & compression
+// otlptracegrpc would use for its own connection. grpc.WithBlock() in cfg.GRPCDialOptions makes it wait for the
+// connection to be established, within ctx.
+func dialGRPC(ctx context.Context, cfg ExporterConfig, tlsConfig *tls.Config) (*grpc.ClientConn, error) {
+	creds := credentials.NewClientTLSFromCert(nil, "")
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	} else if cfg.Insecure {
+		creds = insecure.NewCredentials()
+	}
+	options := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if cfg.Compression == CompressionGzip {
+		options = append(options, grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name)))
+	}
+	options = append(options, cfg.GRPCDialOptions...)
+	// Unlike grpc.NewClient, grpc.DialContext honors grpc.WithBlock(). The "dns" scheme is grpc.NewClient's default one
+	return grpc.DialContext(ctx, "dns:///"+cfg.Endpoint, options...) //nolint:staticcheck
+}
+
+// newGRPCClient creates an OTLP trace client sending traces over gRPC.
+// If tlsConfig is nil, TLS is used with the system's root CAs unless cfg.Insecure is set.
+func newGRPCClient(cfg ExporterConfig, tlsConfig *tls.Config) otlptrace.Client {
+	secureOption := otlptracegrpc.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, ""))
+	if tlsConfig != nil {
+		secureOption = otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig))
+	} else if cfg.Insecure {
+		secureOption = otlptracegrpc.WithInsecure()
+	}
+	options := []otlptracegrpc.Option{secureOption, otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+	if len(cfg.GRPCDialOptions) > 0 {
+		options = append(options, otlptracegrpc.WithDialOption(cfg.GRPCDialOptions...))
+	}
+	if cfg.GRPCConn != nil {
+		options = append(options, otlptracegrpc.WithGRPCConn(cfg.GRPCConn))
+	}
+	if len(cfg.Headers) > 0 {
+		options = append(options, otlptracegrpc.WithHeaders(cfg.Headers))
+	}
+	if cfg.Compression == CompressionGzip {
+		options = append(options, otlptracegrpc.WithCompressor("gzip"))
+	}
+	if cfg.ExportTimeout > 0 {
+		options = append(options, otlptracegrpc.WithTimeout(cfg.ExportTimeout))
+	}
+	if cfg.RetryConfig != nil {
+		// Failed exports are retried by the retryExporter wrapping the exporter instead (see withRetry)
+		options = append(options, otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig{Enabled: false}))
+	}
+	return otlptracegrpc.NewClient(options...)
+}
+
+// newHTTPClient creates an OTLP trace client sending traces over HTTP.
+// TLS is handled the same as for gRPC.
+func newHTTPClient(cfg ExporterConfig, tlsConfig *tls.Config) otlptrace.Client {
+	// Eg: "localhost:4318/v1/traces" -> "localhost:4318" & "v1/traces"
+	endpoint, urlPath, _ := strings.Cut(cfg.Endpoint, "/")
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	if urlPath != "" {
+		options = append(options, otlptracehttp.WithURLPath("/"+urlPath))
+	}
+	if tlsConfig != nil {
+		options = append(options, otlptracehttp.WithTLSClientConfig(tlsConfig))
+	} else if cfg.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		options = append(options, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+	if cfg.Compression == CompressionGzip {
+		options = append(options, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+	}
+	if cfg.ExportTimeout > 0 {
+		options = append(options, otlptracehttp.WithTimeout(cfg.ExportTimeout))
+	}
+	if cfg.RetryConfig != nil {
+		// Failed exports are retried by the retryExporter wrapping the exporter instead (see withRetry)
+		options = append(options, otlptracehttp.WithRetry(otlptracehttp.RetryConfig{Enabled: false}))
+	}
+	return otlptracehttp.NewClient(options...)
+}
+
+// multiExporter fans out spans to multiple exporters.
+// A failure of one exporter is logged and doesn't prevent the others from receiving spans.
+type multiExporter struct {
+	exporters []sdktrace.SpanExporter
+	logger    Logger
+}
+
+func (e *multiExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	var errs []error
+	for i, exporter := range e.exporters {
+		if err := exporter.ExportSpans(ctx, spans); err != nil {
+			e.logger.Errorf("Could not export spans to exporter #%d: %s", i, err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e *multiExporter) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, exporter := range e.exporters {
+		if err := exporter.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,359 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpHTTPReceiver is a fake OTLP/HTTP collector recording the names of the spans it receives.
+type otlpHTTPReceiver struct {
+	mu    sync.Mutex
+	paths []string
+	spans []string
+}
+
+func (r *otlpHTTPReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var export coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &export); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.paths = append(r.paths, req.URL.Path)
+	for _, resourceSpans := range export.ResourceSpans {
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			for _, span := range scopeSpans.Spans {
+				r.spans = append(r.spans, span.Name)
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+}
+
+func TestTransportHTTPExportsSpans(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint func(server *httptest.Server) string
+		insecure bool
+		wantPath string
+	}{
+		{
+			name:     "host:port",
+			endpoint: func(server *httptest.Server) string { return server.Listener.Addr().String() },
+			insecure: true,
+			wantPath: "/v1/traces",
+		},
+		{
+			name:     "host:port with path",
+			endpoint: func(server *httptest.Server) string { return server.Listener.Addr().String() + "/otlp/v1/traces" },
+			insecure: true,
+			wantPath: "/otlp/v1/traces",
+		},
+		{
+			// The "http" scheme implies Insecure
+			name:     "http URL",
+			endpoint: func(server *httptest.Server) string { return server.URL + "/v1/traces" },
+			wantPath: "/v1/traces",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := &otlpHTTPReceiver{}
+			server := httptest.NewServer(receiver)
+			defer server.Close()
+
+			ctx := context.Background()
+			m, err := New(ctx, Config{
+				Logger:    NopLogger,
+				Transport: TransportHTTP,
+				Endpoint:  tt.endpoint(server),
+				Insecure:  tt.insecure,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, span := m.Start(ctx, "operation")
+			span.End()
+			// Shutdown exports the spans still buffered in the batch processor
+			if err := m.Shutdown(ctx); err != nil {
+				t.Fatalf("Shutdown() = %s", err)
+			}
+
+			receiver.mu.Lock()
+			defer receiver.mu.Unlock()
+			if len(receiver.spans) != 1 || receiver.spans[0] != "operation" {
+				t.Errorf("received spans %q, want [operation]", receiver.spans)
+			}
+			if len(receiver.paths) == 0 || receiver.paths[0] != tt.wantPath {
+				t.Errorf("received requests on %q, want %s", receiver.paths, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestRetryConfigMaxAttemptsAppliesToOTLP(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	m, err := New(ctx, Config{
+		Logger:        NopLogger,
+		Transport:     TransportHTTP,
+		Endpoint:      server.Listener.Addr().String(),
+		Insecure:      true,
+		ProcessorMode: ProcessorSimple,
+		RetryConfig:   &RetryConfig{Enabled: true, InitialInterval: time.Millisecond, MaxAttempts: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := m.Start(ctx, "operation")
+	span.End()
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() = %s", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if requests != 3 {
+		t.Errorf("received %d export requests, want 3", requests)
+	}
+}
+
+// otlpGRPCReceiver is a fake OTLP/gRPC collector recording the names of the spans it receives.
+// It doubles as the server's stats.Handler to record the compression (grpc-encoding) of each request.
+type otlpGRPCReceiver struct {
+	coltracepb.UnimplementedTraceServiceServer
+	mu        sync.Mutex
+	spans     []string
+	encodings []string
+	conns     int
+}
+
+func (r *otlpGRPCReceiver) HandleRPC(_ context.Context, rpcStats stats.RPCStats) {
+	if header, ok := rpcStats.(*stats.InHeader); ok {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.encodings = append(r.encodings, header.Compression)
+	}
+}
+
+func (r *otlpGRPCReceiver) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (r *otlpGRPCReceiver) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (r *otlpGRPCReceiver) HandleConn(_ context.Context, connStats stats.ConnStats) {
+	if _, ok := connStats.(*stats.ConnBegin); ok {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.conns++
+	}
+}
+
+func (r *otlpGRPCReceiver) Export(_ context.Context, export *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, resourceSpans := range export.ResourceSpans {
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			for _, span := range scopeSpans.Spans {
+				r.spans = append(r.spans, span.Name)
+			}
+		}
+	}
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func TestTransportGRPCExportsSpans(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver := &otlpGRPCReceiver{}
+	server := grpc.NewServer(grpc.StatsHandler(receiver))
+	coltracepb.RegisterTraceServiceServer(server, receiver)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	ctx := context.Background()
+	m, err := New(ctx, Config{
+		Logger:          NopLogger,
+		Endpoint:        listener.Addr().String(),
+		Insecure:        true,
+		Compression:     CompressionGzip,
+		GRPCDialOptions: []grpc.DialOption{grpc.WithBlock()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := m.Start(ctx, "operation")
+	span.End()
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() = %s", err)
+	}
+
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	if len(receiver.spans) != 1 || receiver.spans[0] != "operation" {
+		t.Errorf("received spans %q, want [operation]", receiver.spans)
+	}
+	if len(receiver.encodings) != 1 || receiver.encodings[0] != "gzip" {
+		t.Errorf("received encodings %q, want [gzip]", receiver.encodings)
+	}
+}
+
+func TestLogsAndMetricsShareTheGRPCConn(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver := &otlpGRPCReceiver{}
+	server := grpc.NewServer(grpc.StatsHandler(receiver))
+	coltracepb.RegisterTraceServiceServer(server, receiver)
+	collogspb.RegisterLogsServiceServer(server, &collogspb.UnimplementedLogsServiceServer{})
+	colmetricspb.RegisterMetricsServiceServer(server, &colmetricspb.UnimplementedMetricsServiceServer{})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	ctx := context.Background()
+	m, err := New(ctx, Config{
+		Logger:        NopLogger,
+		Endpoint:      listener.Addr().String(),
+		Insecure:      true,
+		EnableLogs:    true,
+		EnableMetrics: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := m.Start(ctx, "operation")
+	span.End()
+	var record otellog.Record
+	record.SetBody(attribute.StringValue("message"))
+	m.LoggerProvider.Logger("test").Emit(ctx, record)
+	counter, err := m.MeterProvider.Meter("test").Int64Counter("operations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter.Add(ctx, 1)
+	// Shutdown exports everything; the fake collector rejects logs & metrics (Unimplemented), which doesn't matter here
+	_ = m.Shutdown(ctx)
+
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	if receiver.conns != 1 {
+		t.Errorf("the collector received %d connections, want 1", receiver.conns)
+	}
+}
+
+func TestHealthyChecksDialedGRPCConn(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(server, &otlpGRPCReceiver{})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	ctx := context.Background()
+	m, err := New(ctx, Config{
+		Logger:          NopLogger,
+		Endpoint:        listener.Addr().String(),
+		Insecure:        true,
+		GRPCDialOptions: []grpc.DialOption{grpc.WithBlock()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Healthy(ctx); err != nil {
+		t.Errorf("Healthy() = %s, want nil", err)
+	}
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() = %s", err)
+	}
+	// The collector is still reachable, but the exporter's connection is closed
+	if err := m.Healthy(ctx); err == nil {
+		t.Error("Healthy() = nil after Shutdown, want an error")
+	}
+}
+
+func TestCompressionRejectedWithGRPCConn(t *testing.T) {
+	conn, err := grpc.NewClient("127.0.0.1:4317", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := (Config{GRPCConn: conn, Compression: CompressionGzip}).Validate(); err == nil {
+		t.Error("Validate() = nil, want an error for Compression with GRPCConn")
+	}
+	if err := (Config{GRPCConn: conn}).Validate(); err != nil {
+		t.Errorf("Validate() = %s, want nil", err)
+	}
+}
+
+func TestBackendJaegerSendsUDPPackets(t *testing.T) {
+	// Fake Jaeger agent
+	agent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer agent.Close()
+
+	ctx := context.Background()
+	m, err := New(ctx, Config{Logger: NopLogger, Backend: BackendJaeger, Endpoint: agent.LocalAddr().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := m.Start(ctx, "jaeger-operation")
+	span.End()
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() = %s", err)
+	}
+
+	packet := make([]byte, 65000)
+	_ = agent.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := agent.ReadFrom(packet)
+	if err != nil {
+		t.Fatalf("no packet received by the agent: %s", err)
+	}
+	// The Thrift compact encoded batch contains the span name as is
+	if !bytes.Contains(packet[:n], []byte("jaeger-operation")) {
+		t.Errorf("received packet (%d bytes) doesn't contain the span name", n)
+	}
+}
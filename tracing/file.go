@@ -0,0 +1,156 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// NewFileExporter creates an exporter writing spans to the file at path in the OTLP JSON file format (see Config.FileOutput),
+// Eg: to capture spans for later offline analysis on edge deployments without a collector, or as one of AdditionalExporters.
+// If maxMB > 0, the file is rotated before it grows beyond maxMB megabytes: it is renamed to path.1 (path.1 to path.2, etc.),
+// keeping at most maxBackups rotated files (the file is truncated instead if maxBackups is 0).
+// ctx bounds opening the file; the file is closed when the exporter is shut down.
+func NewFileExporter(ctx context.Context, path string, maxMB, maxBackups int) (sdktrace.SpanExporter, error) {
+	return otlptrace.New(ctx, newFileClient(path, false, maxMB, maxBackups))
+}
+
+// fileClient is an OTLP trace client writing spans to a file instead of sending them over the network, in the
+// OTLP JSON file format (https://opentelemetry.io/docs/specs/otel/protocol/file-exporter/):
+// each export is written as one OTLP/JSON-encoded TracesData per line.
+type fileClient struct {
+	path       string
+	truncate   bool  // whether to truncate the file when opening it (instead of appending to it)
+	maxBytes   int64 // size beyond which the file is rotated (0 meaning never)
+	maxBackups int   // number of rotated files to keep
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+var _ otlptrace.Client = (*fileClient)(nil)
+
+// newFileClient creates an OTLP trace client writing spans to the file at path, rotated as described by
+// NewFileExporter. The file is opened by Start.
+func newFileClient(path string, truncate bool, maxMB, maxBackups int) *fileClient {
+	return &fileClient{
+		path:       path,
+		truncate:   truncate,
+		maxBytes:   int64(maxMB) << 20,
+		maxBackups: maxBackups,
+	}
+}
+
+func (c *fileClient) Start(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.open(c.truncate)
+}
+
+// open opens the file for appending, truncating it if truncate is set.
+func (c *fileClient) open(truncate bool) error {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if truncate {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(c.path, flags, 0o644)
+	if err != nil {
+		return fmt.Errorf("could not open trace file: %s", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("could not open trace file: %s", err)
+	}
+	c.file = file
+	c.size = info.Size()
+	return nil
+}
+
+// rotate renames the file to path.1 (path.1 to path.2, etc.), dropping the oldest rotated file, and opens a new,
+// empty file. If the rotation fails, the file at path is reopened for appending, so that later exports still succeed
+// (and retry the rotation).
+func (c *fileClient) rotate() error {
+	err := c.renameFiles()
+	if err == nil {
+		err = c.open(true)
+	}
+	if err != nil {
+		if reopenErr := c.open(false); reopenErr != nil {
+			return errors.Join(err, reopenErr)
+		}
+	}
+	return err
+}
+
+// renameFiles closes the file, and renames it to path.1 (path.1 to path.2, etc.) if rotated files are kept.
+func (c *fileClient) renameFiles() error {
+	err := c.file.Close()
+	c.file = nil
+	if err != nil {
+		return fmt.Errorf("could not close trace file: %s", err)
+	}
+
+	if c.maxBackups > 0 {
+		for i := c.maxBackups - 1; i >= 1; i-- {
+			err := os.Rename(c.backupPath(i), c.backupPath(i+1))
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("could not rotate trace file: %s", err)
+			}
+		}
+		if err := os.Rename(c.path, c.backupPath(1)); err != nil {
+			return fmt.Errorf("could not rotate trace file: %s", err)
+		}
+	}
+	return nil
+}
+
+// backupPath returns the path of the i-th most recent rotated file.
+func (c *fileClient) backupPath(i int) string {
+	return c.path + "." + strconv.Itoa(i)
+}
+
+func (c *fileClient) Stop(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return nil
+	}
+	err := c.file.Close()
+	c.file = nil
+	return err
+}
+
+func (c *fileClient) UploadTraces(_ context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	line, err := protojson.Marshal(&tracepb.TracesData{ResourceSpans: protoSpans})
+	if err != nil {
+		return fmt.Errorf("could not encode spans: %s", err)
+	}
+	line = append(line, '\n')
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return errors.New("trace file is closed")
+	}
+	if c.maxBytes > 0 && c.size > 0 && c.size+int64(len(line)) > c.maxBytes {
+		if err := c.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := c.file.Write(line)
+	c.size += int64(n)
+	return err
+}
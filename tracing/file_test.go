@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func TestFileClientRecoversFromFailedRotation(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	// Every export (but the first one) rotates the file
+	client := &fileClient{path: path, maxBytes: 1, maxBackups: 1}
+	if err := client.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Stop(ctx)
+	spans := []*tracepb.ResourceSpans{{}}
+	if err := client.UploadTraces(ctx, spans); err != nil {
+		t.Fatal(err)
+	}
+
+	// A non-empty directory in the way of the rotated file makes the rename fail
+	if err := os.MkdirAll(filepath.Join(path+".1", "dir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UploadTraces(ctx, spans); err == nil {
+		t.Fatal("UploadTraces() = nil, want the rotation error")
+	}
+
+	if err := os.RemoveAll(path + ".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UploadTraces(ctx, spans); err != nil {
+		t.Fatalf("UploadTraces() after a failed rotation = %s, want nil", err)
+	}
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("the file wasn't rotated: %s", err)
+	}
+}
@@ -2,27 +2,46 @@ package tracing
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
-	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	"google.golang.org/grpc/credentials"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
 )
 
 var (
 	// GRPCTracingEndpoint - the endpoint to send traces to.
 	GRPCTracingEndpoint string
 
+	// HTTPTracingEndpoint - the endpoint to send traces to when using TransportHTTP.
+	HTTPTracingEndpoint string
+
+	// ZipkinTracingEndpoint - the Zipkin collector URL to send traces to when using BackendZipkin.
+	ZipkinTracingEndpoint string
+
+	// JaegerTracingEndpoint - the Jaeger agent address to send traces to when using BackendJaeger.
+	JaegerTracingEndpoint string
+
+	// DefaultServiceName - the "service.name" attribute used when Config.Attributes doesn't contain one.
+	// Defaults to the binary name.
+	DefaultServiceName = filepath.Base(os.Args[0])
+
 	// DefaultSampler - default sampling strategy.
 	// If a span doesn't have a parent, turn on sampling.
 	// Otherwise, turn on sampling only if the parent is being sampled.
@@ -31,91 +50,645 @@ var (
 	// DefaultBatchTimeout - max duration for constructing a batch.
 	// Processor forcefully sends available spans when timeout is reached (default: 5000 ms).
 	DefaultBatchTimeout = sdktrace.DefaultScheduleDelay * time.Millisecond
+
+	// DefaultConnectTimeout - default max duration for creating (& connecting) the exporters in New.
+	DefaultConnectTimeout = 10 * time.Second
+
+	// DefaultShutdownTimeout - max duration for flushing the buffered spans in Manager.ShutdownOnSignal.
+	DefaultShutdownTimeout = 5 * time.Second
 )
 
+// serviceNameKey - the resource attribute identifying the service sending the traces.
+// Backends behave poorly (Eg: report "unknown_service") when it's missing.
+const serviceNameKey = "service.name"
+
 func init() {
-	// If NODE_IP isn't set, use "localhost:4317" for GRPCTracingEndpoint
+	// If NODE_IP isn't set, use "localhost:4317" for GRPCTracingEndpoint (and "localhost" for the other endpoints)
 	host := "localhost"
 	nodeIp, ok := os.LookupEnv("NODE_IP")
 	if ok {
 		host = nodeIp
 	}
 	GRPCTracingEndpoint = net.JoinHostPort(host, "4317")
+	HTTPTracingEndpoint = net.JoinHostPort(host, "4318")
+	ZipkinTracingEndpoint = "http://" + net.JoinHostPort(host, "9411") + "/api/v2/spans"
+	JaegerTracingEndpoint = net.JoinHostPort(host, "6831")
 }
 
 type Manager struct {
 	TracerProvider *sdktrace.TracerProvider
 	Processor      sdktrace.SpanProcessor
 	Propagator     propagation.TextMapPropagator
+
+	// Processors holds all the span processors registered on the TracerProvider, in registration order:
+	// the baggage processor (if Config.CopyBaggageKeys is set), Config.SpanProcessors, Processor, then one per
+	// Config.AdditionalExporters.
+	Processors []sdktrace.SpanProcessor
+
+	// Exporter the Processor sends spans to.
+	// If multiple exporters are configured (see Config.Exporters), this fans out to all of them.
+	Exporter sdktrace.SpanExporter
+
+	// LoggerProvider exporting logs to the same collector as the traces, sharing their resource (nil unless Config.EnableLogs is set).
+	// Eg: pass it to a log bridge (Eg: otelslog.NewHandler(name, otelslog.WithLoggerProvider(m.LoggerProvider)))
+	// to export logs correlated with the traces (the bridges record the trace context of the logging call's ctx).
+	LoggerProvider *sdklog.LoggerProvider
+
+	// MeterProvider exporting metrics to the same collector as the traces, sharing their resource (nil unless
+	// Config.EnableMetrics is set). Eg: m.MeterProvider.Meter("my-service").Int64Counter("orders.processed")
+	MeterProvider *sdkmetric.MeterProvider
+
+	// InMemoryExporter holds the spans exported so far when using BackendInMemory (nil otherwise).
+	// Eg: in tests, call ForceFlush and then InMemoryExporter.GetSpans() to assert on span names, attributes, parents, etc.
+	InMemoryExporter *tracetest.InMemoryExporter
+
+	// serviceName is the "service.name" resource attribute
+	serviceName string
+	// logger is Config.Logger (nil meaning the default logger, see logger)
+	logger Logger
+	// sampler is the sampler configured by Config, wrapped by the sampler of the TracerProvider (see Sampler)
+	sampler sdktrace.Sampler
+	// exporterConfigs describe the exporters the manager exports to (see Healthy), with the gRPC connections of conns
+	exporterConfigs []ExporterConfig
+	// conns are the gRPC connections dialed by New for the OTLP/gRPC exporters, closed by Shutdown
+	conns []*grpc.ClientConn
+	// instrumentationVersion is Config.InstrumentationVersion
+	instrumentationVersion string
+	// config is the Config the manager was created with (see Clone)
+	config Config
+
+	installGlobalsOnce sync.Once
+	shutdownOnce       sync.Once
+	shutdownErr        error
 }
 
 type Config struct {
+	// Whether New should fill the fields left at their zero value from the standard OpenTelemetry environment
+	// variables (Eg: OTEL_EXPORTER_OTLP_ENDPOINT, OTEL_TRACES_SAMPLER). See ConfigFromEnv for the supported variables.
+	// Explicitly set fields always win.
+	UseEnvFallback bool
+
+	// Called with the errors the OpenTelemetry SDK can't return to the caller, most notably failures to export spans
+	// (Eg: to log them or increment a metric, to alert on silent span loss).
+	// Note: it is registered as the otel global error handler (otel.SetErrorHandler), i.e. it receives errors from
+	// all the OpenTelemetry components of the process. If nil, the default global error handler (logging to stderr) is kept.
+	ErrorHandler func(error)
+
+	// Logger the package writes its internal logs to (Eg: startup info, ignored configuration, failed exports).
+	// Eg: a *logrus.Entry with the service's fields, an adapter for another logger, or NopLogger to silence them.
+	// If nil, defaults to the global logrus logger.
+	Logger Logger
+
+	// Whether New should set the manager's TracerProvider & Propagator as the otel globals. See Manager.InstallGlobals.
+	SetGlobal bool
+
+	// Whether to completely disable tracing, Eg: at runtime via OTEL_SDK_DISABLED (see ConfigFromEnv).
+	// If true, all the other fields (except SetGlobal) are ignored and New returns a Manager backed by a no-op
+	// TracerProvider & exporter, so that all tracing calls are (nearly) zero-cost and the wiring code remains
+	// identical whether tracing is on or off.
+	Disabled bool
+
 	// Endpoint to send traces to.
 	// Eg: localhost:4317
 	// If empty, this will be set to GRPCTracingEndpoint
+	// (or HTTPTracingEndpoint for TransportHTTP, ZipkinTracingEndpoint for BackendZipkin,
+	// JaegerTracingEndpoint for BackendJaeger)
+	//
+	// With BackendZipkin, the endpoint must be the full collector URL.
+	// Eg: http://localhost:9411/api/v2/spans
+	// With BackendJaeger, the endpoint is the Jaeger agent's (Thrift compact over UDP) address.
+	// Eg: localhost:6831
+	//
+	// With TransportHTTP, the endpoint may additionally carry the URL path to send traces to.
+	// Eg: localhost:4318/v1/traces
+	// If the path is omitted, the OTLP default (/v1/traces) is used. A URL is accepted too, Eg: http://localhost:4318/v1/traces
+	// (the "http" scheme implying Insecure). Otherwise, the endpoint must not have a scheme: New fails on a malformed endpoint.
 	Endpoint string
 
+	// Backend to send traces to.
+	// If unset, defaults to BackendOTLP
+	Backend Backend
+
+	// Protocol used to send traces to Endpoint. Only applies to BackendOTLP.
+	// If unset, defaults to TransportGRPC for backward compatibility.
+	// Note: collectors usually expose OTLP/gRPC on port 4317 and OTLP/HTTP on port 4318.
+	Transport Transport
+
 	// Whether to disable client transport security (i.e. not use TLS credentials)
-	// for the exporter's gRPC connection to the server.
+	// for the exporter's gRPC/HTTP connection to the server.
 	Insecure bool
 
+	// PEM encoded files for mutual TLS (client certificate authentication) with the server.
+	// TLSClientCertFile & TLSClientKeyFile must be set together; TLSCACertFile is used to verify the server
+	// instead of the system's root CAs. Can't be combined with Insecure. Only applies to BackendOTLP.
+	TLSClientCertFile string
+	TLSClientKeyFile  string
+	TLSCACertFile     string
+
+	// TLS configuration used for the connection to the server.
+	// Eg: with certificates loaded into memory from a secrets manager.
+	// Takes precedence over the TLS files above. Can't be combined with Insecure. Only applies to BackendOTLP.
+	TLSConfig *tls.Config
+
+	// Headers to send on every export request. Eg: an "Authorization" bearer token, a tenant header, etc.
+	// Only applies to BackendOTLP; ignored if DebugOutput is set since no network export happens.
+	Headers map[string]string
+
+	// Additional options for the exporter's gRPC connection.
+	// Eg: grpc.WithKeepaliveParams(...), grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(...)), grpc.WithChainUnaryInterceptor(...)
+	// Only applies to BackendOTLP with TransportGRPC; ignored if DebugOutput is set.
+	GRPCDialOptions []grpc.DialOption
+
+	// Existing gRPC connection to the collector to reuse for exporting traces instead of opening a new one.
+	// If set, Endpoint, Insecure, the TLS fields and GRPCDialOptions are ignored, and Compression must be left unset
+	// (configure it on the connection instead, Eg: grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name))).
+	// Only applies to BackendOTLP with TransportGRPC.
+	// Note: the caller remains responsible for closing the connection, after Manager.Shutdown.
+	GRPCConn *grpc.ClientConn
+
+	// Compression applied to export requests. Only applies to BackendOTLP.
+	// If unset, defaults to CompressionNone
+	Compression Compression
+
 	// Identifying information/metadata about the thing sending the traces.
 	// A list of common attributes can be found here.
 	//
 	// https://opentelemetry.io/docs/specs/semconv/resource/#semantic-attributes-with-sdk-provided-default-value
+	//
+	// If "service.name" isn't set, it defaults to the OTEL_SERVICE_NAME environment variable if set,
+	// or DefaultServiceName otherwise (an explicitly set value always wins).
 	Attributes map[string]string
 
+	// Additional non-string attributes. Eg: attribute.Int("process.pid", os.Getpid()), attribute.Bool(...)
+	// Appended after Attributes, so they win if both contain the same key.
+	TypedAttributes []attribute.KeyValue
+
+	// Pre-built resource describing the thing sending the traces.
+	// Eg: built using resource.New(ctx, resource.WithDetectors(...), resource.WithAttributes(attribute.Int(...)))
+	// If non-nil, it is used instead of the SDK-provided default resource (resource.Default()),
+	// with Attributes (and detected attributes) merged over it (values in Attributes win on conflict).
+	// Note: "service.name" isn't defaulted when Resource is set.
+	Resource *resource.Resource
+
+	// Whether to detect Kubernetes attributes (k8s.pod.name, k8s.namespace.name, k8s.node.name) when running inside a Pod,
+	// preferably from the POD_NAME, POD_NAMESPACE & NODE_NAME environment variables (Eg: set using the downward API).
+	// Detected attributes are merged with Attributes, values in Attributes win on conflict.
+	AutoDetectK8s bool
+
+	// Whether to detect process (Eg: process.pid), OS (Eg: os.type), host (Eg: host.name) and container (Eg: container.id) attributes.
+	// Detected attributes are merged with Attributes, values in Attributes win on conflict.
+	AutoDetectProcess bool
+
+	// Additional resource detectors, run after the Kubernetes & process ones.
+	// Eg: []resource.Detector{gcp.NewDetector()} (from go.opentelemetry.io/contrib/detectors/gcp)
+	// Detected attributes are merged with Attributes, values in Attributes win on conflict.
+	// A failing detector doesn't fail New: the error is logged and the attributes of the other detectors are used.
+	Detectors []resource.Detector
+
 	// If nil, defaults to DefaultSampler
 	// Eg: sdktrace.AlwaysSample()
+	// Gives full control over sampling: ignored (with a warning) if SamplingPolicy is set, and can't be combined with
+	// SampleRatio (Eg: use an AdjustableSampler for a ratio of root spans changing at runtime).
 	Sampler sdktrace.Sampler
 
+	// ParentBased sampling with explicit samplers for root spans and spans with a remote/local, sampled/not sampled parent.
+	// Eg: &SamplingPolicy{RemoteParentSampled: sdktrace.TraceIDRatioBased(0.1)} to sample every root span
+	// but only 10% of the spans with a remote parent. See SamplingPolicy.
+	// If set, takes precedence over Sampler & SampleRatio, SampleRatio then applying to root spans if SamplingPolicy.Root is nil.
+	SamplingPolicy *SamplingPolicy
+
+	// Ratio (between 0 and 1) of root spans to sample, shortcut for:
+	// sdktrace.ParentBased(sdktrace.TraceIDRatioBased(SampleRatio))
+	// Child spans follow their parent's sampling decision. Useful for high-traffic services, for which
+	// DefaultSampler (sampling every root span) is too aggressive.
+	// Can't be combined with Sampler. With SamplingPolicy, it only applies to root spans (see SamplingPolicy.Root).
+	SampleRatio float64
+
+	// Whether to export the spans that recorded an error (i.e. with an exception event or an Error status, Eg: see
+	// Manager.RecordError) even if they weren't sampled. See AlwaysSampleOnError for the overhead.
+	// Implied by a Sampler created with AlwaysSampleOnError.
+	AlwaysSampleErrors bool
+
+	// Max number of traces (i.e. root spans) to sample per second, regardless of the traffic. See RateLimitedSampler.
+	// Applied as an additional gate after the sampling decision of SampleRatio/Sampler/DefaultSampler for root spans;
+	// child spans follow their parent's decision (with a ParentBased sampler, Eg: the default ones), so that sampled
+	// traces are complete. If unset, sampled spans aren't rate limited.
+	SpansPerSecond float64
+
+	// Caps on the attributes, events and links recorded per span, so that misbehaving instrumentation
+	// (Eg: attaching thousands of attributes) can't blow up memory and collector ingestion.
+	// Applied as is (sdktrace.WithRawSpanLimits): start from sdktrace.NewSpanLimits() and override the limits to change,
+	// since zero values aren't defaulted (Eg: AttributeCountLimit: 0 drops all attributes). A negative limit means unlimited.
+	// If nil, defaults to the SDK defaults (Eg: 128 attributes/events/links per span, unlimited attribute value length)
+	// or the OTEL_SPAN_*_LIMIT / OTEL_ATTRIBUTE_*_LIMIT environment variables if set.
+	SpanLimits *sdktrace.SpanLimits
+
 	BatchTimeout time.Duration
 
-	// If DebugOutput is non-nil, Endpoint will be ignored and trace output will
+	// Max number of spans buffered by the batch processor; spans are dropped once it's full (Eg: during bursts).
+	// If unset, defaults to the SDK default (sdktrace.DefaultMaxQueueSize: 2048)
+	MaxQueueSize int
+
+	// Max number of spans exported in a single batch. Must not exceed MaxQueueSize.
+	// If unset, defaults to the SDK default (sdktrace.DefaultMaxExportBatchSize: 512)
+	MaxExportBatchSize int
+
+	// How spans are handed over to the exporter. See ProcessorMode for the latency vs. reliability trade-offs.
+	// If unset, defaults to ProcessorBatch. BatchTimeout is ignored with ProcessorSimple.
+	ProcessorMode ProcessorMode
+
+	// Max duration a single export request may take before failing, so that a slow collector
+	// doesn't hang the processor. Bounds exports of the batch processor, and requests of BackendOTLP and BackendZipkin.
+	// If unset, defaults to the SDK defaults (30s for the batch processor, 10s for OTLP requests).
+	ExportTimeout time.Duration
+
+	// Max duration for creating the exporter(s) in New, after which New fails with a clear error instead of hanging
+	// (Eg: when the collector's DNS name is wrong). Note: the gRPC connection is established lazily (in the background)
+	// unless grpc.WithBlock() is passed in GRPCDialOptions, in which case this (or the deadline of the ctx passed to New,
+	// if earlier) bounds the wait for the connection.
+	// If unset, defaults to DefaultConnectTimeout
+	ConnectTimeout time.Duration
+
+	// Retry policy for export requests failing with a transient error.
+	// If nil, the OTLP SDK defaults are used for BackendOTLP (retries enabled, 5s initial & 30s max interval,
+	// 1m max elapsed time) and failed exports aren't retried for BackendZipkin & BackendJaeger.
+	RetryConfig *RetryConfig
+
+	// If DebugOutput is non-nil, Backend and Endpoint will be ignored and trace output will
 	// instead be written to the io.Writer.
+	// With BackendStdout, output is written to DebugOutput if set, or os.Stdout otherwise.
 	DebugOutput io.Writer
+
+	// Format of the debug output (see DebugOutput & BackendStdout).
+	// If unset, defaults to DebugFormatPretty
+	DebugFormat DebugFormat
+
+	// Path of a file to write traces to instead of sending them over the network (Eg: in air-gapped environments),
+	// in the OTLP JSON file format: one OTLP/JSON-encoded TracesData per line, which can be replayed to a collector
+	// later (Eg: with the collector's otlpjsonfile receiver). Unlike DebugOutput, the output is machine-readable OTLP.
+	// If set, Backend and Endpoint are ignored. The file is created if needed and closed by Manager.Shutdown.
+	// Ignored if DebugOutput is set.
+	FileOutput string
+
+	// Whether to truncate FileOutput when New opens it, instead of appending to it.
+	FileTruncate bool
+
+	// Max size (in megabytes) of FileOutput, before which it is rotated: renamed to FileOutput.1 (FileOutput.1 to
+	// FileOutput.2, etc.), keeping at most FileMaxBackups rotated files. See NewFileExporter.
+	// If unset, the file isn't rotated.
+	FileMaxMB int
+
+	// Max number of rotated files kept, see FileMaxMB. If unset, FileOutput is truncated (instead of rotated) when full.
+	FileMaxBackups int
+
+	// Format(s) used to propagate trace context to remote processes. Eg: PropagationW3C | PropagationB3Single
+	// If unset, defaults to PropagationW3C. W3C Baggage is propagated regardless of the format.
+	PropagationFormat PropagationFormat
+
+	// Names of the propagators to use, in order, Eg: []string{"tracecontext", "baggage", "jaeger"}
+	// Supported names: "tracecontext", "baggage", "b3" (single header), "b3multi", "jaeger"
+	// (same as the OTEL_PROPAGATORS environment variable).
+	// If set, takes precedence over PropagationFormat. If empty, defaults to W3C TraceContext + Baggage.
+	Propagators []string
+
+	// Custom span processors (Eg: redaction, sampling adjustments, metrics extraction) to register on the TracerProvider,
+	// in order, before the processor(s) exporting spans.
+	SpanProcessors []sdktrace.SpanProcessor
+
+	// Version of the instrumentation (Eg: the service or library version) set on the tracers returned by
+	// Manager.Tracer (and used by Manager.Start & Manager.StartSpan), reported as "otel.scope.version" by the backends.
+	// Eg: "1.2.3"
+	// If empty, tracers are unversioned.
+	InstrumentationVersion string
+
+	// Function rewriting span names before they're exported, Eg: to strip PII (such as user IDs) from the names
+	// produced by auto-instrumented routes ("/users/12345/email" -> "/users/{id}/email"). See RegexpSanitizer.
+	// Applied by the processor(s) exporting spans, i.e. custom SpanProcessors see the original names.
+	SpanNameSanitizer func(string) string
+
+	// Baggage members (Eg: user/tenant IDs carried in OTel baggage) to copy onto every span as attributes when it starts.
+	// Eg: []string{"tenant.id", "user.id"}
+	// Members missing from the span's context are skipped. Copied before SpanProcessors run, so that they see the attributes.
+	CopyBaggageKeys []string
+
+	// Pre-built exporters to send traces to, alongside the exporter(s) described by the fields above and Exporters.
+	// Unlike Exporters (which share a single processor), each of them gets its own span processor.
+	// Eg: stdouttrace.New() during incident debugging
+	AdditionalExporters []sdktrace.SpanExporter
+
+	// Whether to also export logs (see Manager.LoggerProvider) to the collector described by the exporter fields above,
+	// with the same connection settings (Eg: Endpoint, Transport, TLS, Headers) and resource as the traces.
+	// With TransportGRPC, the gRPC connection of the traces is shared. With TransportHTTP, a custom URL path ending
+	// with /v1/traces is mapped to the corresponding /v1/logs path. Only supported with BackendOTLP & DebugOutput.
+	EnableLogs bool
+
+	// Whether to also export metrics (see Manager.MeterProvider) to the collector described by the exporter fields above,
+	// as EnableLogs. Only supported with BackendOTLP & DebugOutput.
+	EnableMetrics bool
+
+	// Interval between metric exports, see EnableMetrics.
+	// If unset, defaults to the SDK default (60s, or the OTEL_METRIC_EXPORT_INTERVAL environment variable if set).
+	MetricsInterval time.Duration
+
+	// Additional exporters to send traces to, alongside the one described by the fields above.
+	// Eg: send traces to both a remote collector and a local DebugOutput writer during a canary rollout.
+	// A failure to export to one of them is logged and doesn't prevent the others from receiving spans.
+	Exporters []ExporterConfig
+}
+
+// exporterConfig returns the ExporterConfig described by the exporter fields of c.
+func (c Config) exporterConfig() ExporterConfig {
+	return ExporterConfig{
+		Endpoint:          c.Endpoint,
+		Backend:           c.Backend,
+		Transport:         c.Transport,
+		Insecure:          c.Insecure,
+		TLSClientCertFile: c.TLSClientCertFile,
+		TLSClientKeyFile:  c.TLSClientKeyFile,
+		TLSCACertFile:     c.TLSCACertFile,
+		TLSConfig:         c.TLSConfig,
+		Headers:           c.Headers,
+		GRPCDialOptions:   c.GRPCDialOptions,
+		GRPCConn:          c.GRPCConn,
+		Compression:       c.Compression,
+		ExportTimeout:     c.ExportTimeout,
+		RetryConfig:       c.RetryConfig,
+		DebugOutput:       c.DebugOutput,
+		DebugFormat:       c.DebugFormat,
+		FileOutput:        c.FileOutput,
+		FileTruncate:      c.FileTruncate,
+		FileMaxMB:         c.FileMaxMB,
+		FileMaxBackups:    c.FileMaxBackups,
+	}
 }
 
 func New(ctx context.Context, cfg Config) (*Manager, error) {
-	log.Infof("Initializing Tracer Provider for endpoint: %s...", cfg.Endpoint)
+	config := cfg // as passed, see Manager.Clone
+	if cfg.UseEnvFallback {
+		cfg = cfg.withEnvFallback()
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	logger := cfg.logger()
+	if cfg.Disabled {
+		logger.Infof("Tracing is disabled, initializing no-op Tracer Provider...")
+		m := NewNoop()
+		m.logger = cfg.Logger
+		m.config = config
+		if cfg.SetGlobal {
+			m.InstallGlobals()
+		}
+		return m, nil
+	}
 
-	if cfg.Endpoint == "" {
-		cfg.Endpoint = GRPCTracingEndpoint
+	switch cfg.exporterConfig().backend() {
+	case BackendStdout:
+		if ignored := ignoredWithDebugOutput(cfg); cfg.DebugOutput != nil && len(ignored) > 0 {
+			logger.Warnf("DebugOutput is set, ignoring: %s", strings.Join(ignored, ", "))
+		}
+		logger.Infof("Initializing Tracer Provider writing traces to debug output...")
+	case BackendInMemory:
+		logger.Infof("Initializing Tracer Provider keeping traces in memory...")
+	case BackendFile:
+		logger.Infof("Initializing Tracer Provider writing traces to file: %s...", cfg.FileOutput)
+	default:
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = defaultEndpoint(cfg.exporterConfig())
+		}
+		logger.Infof("Initializing Tracer Provider for endpoint: %s...", cfg.Endpoint)
 	}
-	if cfg.Sampler == nil {
-		cfg.Sampler = DefaultSampler
+
+	if cfg.ErrorHandler != nil {
+		otel.SetErrorHandler(otel.ErrorHandlerFunc(cfg.ErrorHandler))
 	}
+	configuredSampler := baseSampler(cfg)
+	sampler := newSampler(cfg, configuredSampler)
 	if cfg.BatchTimeout <= 0 {
 		cfg.BatchTimeout = DefaultBatchTimeout
 	}
+	if cfg.ConnectTimeout <= 0 {
+		cfg.ConnectTimeout = DefaultConnectTimeout
+	}
 
-	/* Create either an OTLP gRPC Trace Exporter for sending traces to a collector/remote backend/etc.
+	/* Create either an OTLP gRPC/HTTP Trace Exporter or Zipkin Exporter for sending traces to a collector/remote backend/etc.
 	OR Stdout Trace Exporter for writing traces to std output
 	*/
-	var exporter sdktrace.SpanExporter
+	connectCtx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+	defer cancel()
+	// If New fails, the exporters created so far are shut down (in reverse order, Eg: before closing their gRPC
+	// connection) so that they don't leak (Eg: open files, gRPC connections)
+	var shutdowns []func(context.Context) error
+	defer func() {
+		for i := len(shutdowns) - 1; i >= 0; i-- {
+			if err := shutdowns[i](context.WithoutCancel(ctx)); err != nil {
+				logger.Warnf("Could not shutdown exporter: %s", err)
+			}
+		}
+	}()
+	exporterConfigs := append([]ExporterConfig{cfg.exporterConfig()}, cfg.Exporters...)
+	exporters := make([]sdktrace.SpanExporter, 0, len(exporterConfigs))
+	var conns []*grpc.ClientConn
+	for i := range exporterConfigs {
+		exporterCfg := &exporterConfigs[i]
+		conn, err := dialExporterConn(connectCtx, *exporterCfg)
+		if conn != nil {
+			// The connection is owned (i.e. closed) by the manager; recording it lets Healthy check its state
+			exporterCfg.GRPCConn = conn
+			conns = append(conns, conn)
+			shutdowns = append(shutdowns, func(context.Context) error { return conn.Close() })
+		}
+		var exporter sdktrace.SpanExporter
+		if err == nil {
+			exporter, err = newExporter(connectCtx, *exporterCfg, logger)
+		}
+		if err != nil {
+			if errors.Is(connectCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+				return nil, fmt.Errorf("could not connect trace exporter to %s within %s: %s", exporterCfg.Endpoint, cfg.ConnectTimeout, err)
+			}
+			return nil, fmt.Errorf("could not create trace exporter for Tracer Provider: %s", err)
+		}
+		exporters = append(exporters, exporter)
+		shutdowns = append(shutdowns, exporter.Shutdown)
+	}
+	var logExporter sdklog.Exporter
 	var err error
-	if cfg.DebugOutput == nil {
-		secureOption := otlptracegrpc.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, ""))
-		if cfg.Insecure {
-			secureOption = otlptracegrpc.WithInsecure()
+	if cfg.EnableLogs {
+		// The exporter config of the traces, so that logs & metrics share its gRPC connection (if any)
+		logExporter, err = newLogExporter(connectCtx, exporterConfigs[0])
+		if err != nil {
+			return nil, fmt.Errorf("could not create log exporter: %s", err)
+		}
+		shutdowns = append(shutdowns, logExporter.Shutdown)
+	}
+	var metricExporter sdkmetric.Exporter
+	if cfg.EnableMetrics {
+		metricExporter, err = newMetricExporter(connectCtx, exporterConfigs[0])
+		if err != nil {
+			return nil, fmt.Errorf("could not create metric exporter: %s", err)
+		}
+		shutdowns = append(shutdowns, metricExporter.Shutdown)
+	}
+	var inMemoryExporter *tracetest.InMemoryExporter
+	for _, exporter := range exporters {
+		if e, ok := exporter.(*tracetest.InMemoryExporter); ok && inMemoryExporter == nil {
+			inMemoryExporter = e
 		}
-		traceClient := otlptracegrpc.NewClient(secureOption, otlptracegrpc.WithEndpoint(cfg.Endpoint))
-		exporter, err = otlptrace.New(context.Background(), traceClient)
-	} else {
-		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint(), stdouttrace.WithWriter(cfg.DebugOutput))
 	}
+	var exporter sdktrace.SpanExporter = exporters[0]
+	if len(exporters) > 1 {
+		exporter = &multiExporter{exporters: exporters, logger: logger}
+	}
+
+	/* Create the propagator for propagating trace context (and baggage) to remote processes over the wire.
+	 */
+	propagator, err := newPropagator(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("could not create trace exporter for Tracer Provider: %s", err)
+		return nil, err
 	}
 
 	/* Define the resources describing the object that generated the telemetry signals.
 	 */
-	attrs := make([]attribute.KeyValue, len(cfg.Attributes))
+	resources, err := newResource(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	/* Create TracerProvider.
+	TracerProvider is a factory for creating & configuring tracers.
+	Each tracer traces/records info about a single operation or request as it traverses different parts of a distributed system.
+	TraceProvider then samples these traces and send them in batches to the collector/endpoint via the Exporter.
+	*/
+
+	// Note: BatchSpanProcessor processes spans in batches before they are exported. Preferred processor.
+	// SimpleSpanProcessor processes & exports each span as it is created. See ProcessorMode for the trade-offs.
+	processor, err := newProcessor(exporter, cfg) // create the span processor explicitly
+	if err != nil {
+		return nil, err
+	}
+	// Custom processors come first (in order), so that they see (Eg: can modify) spans before they're exported
+	var processors []sdktrace.SpanProcessor
+	if len(cfg.CopyBaggageKeys) > 0 {
+		processors = append(processors, newBaggageProcessor(cfg.CopyBaggageKeys))
+	}
+	processors = append(append(processors, cfg.SpanProcessors...), processor)
+	// Each additional exporter gets its own processor, so that a slow exporter doesn't hold up the others
+	for _, additionalExporter := range cfg.AdditionalExporters {
+		additionalProcessor, err := newProcessor(additionalExporter, cfg)
+		if err != nil {
+			return nil, err
+		}
+		processors = append(processors, additionalProcessor)
+	}
+	providerOptions := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithResource(resources),
+	}
+	if cfg.SpanLimits != nil {
+		providerOptions = append(providerOptions, sdktrace.WithRawSpanLimits(*cfg.SpanLimits))
+	}
+	for _, p := range processors {
+		providerOptions = append(providerOptions, sdktrace.WithSpanProcessor(p)) // OR directly use: sdktrace.WithBatcher(exporter), if processor needn't be returned from the function
+	}
+	traceProvider := sdktrace.NewTracerProvider(providerOptions...)
+
+	serviceName, _ := resources.Set().Value(serviceNameKey)
+	var loggerProvider *sdklog.LoggerProvider
+	if logExporter != nil {
+		loggerProvider = newLoggerProvider(logExporter, resources)
+	}
+	var meterProvider *sdkmetric.MeterProvider
+	if metricExporter != nil {
+		meterProvider = newMeterProvider(metricExporter, cfg.MetricsInterval, resources)
+	}
+
+	// The exporters are now owned by the providers, shut down by Manager.Shutdown
+	shutdowns = nil
+
+	// Specifications for instrumentation: https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/trace/api.md
+	m := &Manager{
+		TracerProvider:         traceProvider,
+		Processor:              processor,
+		Processors:             processors,
+		Propagator:             propagator,
+		Exporter:               exporter,
+		InMemoryExporter:       inMemoryExporter,
+		LoggerProvider:         loggerProvider,
+		MeterProvider:          meterProvider,
+		serviceName:            serviceName.AsString(),
+		logger:                 cfg.Logger,
+		sampler:                configuredSampler,
+		exporterConfigs:        exporterConfigs,
+		conns:                  conns,
+		instrumentationVersion: cfg.InstrumentationVersion,
+		config:                 config,
+	}
+	if cfg.SetGlobal {
+		m.InstallGlobals()
+	}
+	return m, nil
+}
+
+// ignoredWithDebugOutput returns the names of the network exporter fields set on c,
+// which are ignored when DebugOutput is set since no network export happens.
+func ignoredWithDebugOutput(c Config) []string {
+	var fields []string
+	if c.Endpoint != "" {
+		fields = append(fields, "Endpoint")
+	}
+	if c.Backend != BackendOTLP && c.Backend != BackendStdout {
+		fields = append(fields, "Backend")
+	}
+	if c.Transport != TransportGRPC {
+		fields = append(fields, "Transport")
+	}
+	if c.Insecure {
+		fields = append(fields, "Insecure")
+	}
+	if c.TLSClientCertFile != "" || c.TLSClientKeyFile != "" || c.TLSCACertFile != "" || c.TLSConfig != nil {
+		fields = append(fields, "TLS")
+	}
+	if len(c.Headers) > 0 {
+		fields = append(fields, "Headers")
+	}
+	if len(c.GRPCDialOptions) > 0 || c.GRPCConn != nil {
+		fields = append(fields, "GRPCDialOptions/GRPCConn")
+	}
+	if c.Compression != "" && c.Compression != CompressionNone {
+		fields = append(fields, "Compression")
+	}
+	if c.RetryConfig != nil {
+		fields = append(fields, "RetryConfig")
+	}
+	return fields
+}
+
+// newResource creates the resource describing the object that generated the telemetry signals.
+func newResource(ctx context.Context, cfg Config) (*resource.Resource, error) {
+	// Attributes are merged over the pre-built resource if set, or the SDK-provided defaults otherwise
+	base := resource.Default()
+	if cfg.Resource != nil {
+		base = cfg.Resource
+	} else if _, ok := cfg.Attributes[serviceNameKey]; !ok {
+		serviceName := os.Getenv("OTEL_SERVICE_NAME")
+		if serviceName == "" {
+			cfg.logger().Warnf("No %q attribute configured, defaulting to %q", serviceNameKey, DefaultServiceName)
+			serviceName = DefaultServiceName
+		}
+		attributes := make(map[string]string, len(cfg.Attributes)+1)
+		for k, v := range cfg.Attributes {
+			attributes[k] = v
+		}
+		attributes[serviceNameKey] = serviceName
+		cfg.Attributes = attributes
+	}
+	attrs := make([]attribute.KeyValue, len(cfg.Attributes), len(cfg.Attributes)+len(cfg.TypedAttributes))
 	i := 0
 	for k, v := range cfg.Attributes {
 		attrs[i] = attribute.String(k, v)
 		i++
 	}
+	attrs = append(attrs, cfg.TypedAttributes...)
 
 	// Eg:
 	//resources, err := resource.New(ctx,
@@ -125,26 +698,133 @@ func New(ctx context.Context, cfg Config) (*Manager, error) {
 	//		attribute.String("library.language", "go"),
 	//	),
 	//)
-	resources, err := resource.New(ctx, resource.WithAttributes(attrs...))
+	var options []resource.Option
+	if cfg.AutoDetectK8s {
+		options = append(options, resource.WithDetectors(k8sDetector{}))
+	}
+	if cfg.AutoDetectProcess {
+		options = append(options, resource.WithProcess(), resource.WithOS(), resource.WithHost(), resource.WithContainer())
+	}
+	if len(cfg.Detectors) > 0 {
+		options = append(options, resource.WithDetectors(cfg.Detectors...))
+	}
+	// Configured attributes come last so that they win over detected ones
+	options = append(options, resource.WithAttributes(attrs...))
+	resources, err := resource.New(ctx, options...)
 	if err != nil {
-		return nil, err
+		// The resource still contains the attributes of the detectors that succeeded (and the configured ones)
+		if resources == nil {
+			return nil, err
+		}
+		cfg.logger().Warnf("Could not detect some resource attributes, continuing without them: %s", err)
+	}
+	// Merge over the base resource (Eg: telemetry.sdk.name, telemetry.sdk.version), configured attributes win.
+	// Merging fails if both resources have (different) schema URLs, in which case only the configured attributes are used.
+	if merged, err := resource.Merge(base, resources); err != nil {
+		cfg.logger().Warnf("Could not merge configured attributes over the base resource, using configured attributes only: %s", err)
+	} else {
+		resources = merged
 	}
+	return resources, nil
+}
 
-	/* Create TracerProvider.
-	TracerProvider is a factory for creating & configuring tracers.
-	Each tracer traces/records info about a single operation or request as it traverses different parts of a distributed system.
-	TraceProvider then samples these traces and send them in batches to the collector/endpoint via the Exporter.
-	*/
+// InstallGlobals sets the manager's TracerProvider and Propagator as the otel global tracer provider & propagator,
+// i.e. the ones returned by otel.GetTracerProvider() & otel.GetTextMapPropagator() and used by instrumentation libraries.
+// The LoggerProvider & MeterProvider, if any, are set as the global logger provider (see go.opentelemetry.io/otel/log/global)
+// & meter provider (otel.GetMeterProvider()) as well.
+// Optional: the manager itself doesn't rely on the globals. Calling it multiple times is a no-op.
+// See also Config.SetGlobal.
+func (m *Manager) InstallGlobals() {
+	m.installGlobalsOnce.Do(func() {
+		otel.SetTracerProvider(m.TracerProvider)
+		otel.SetTextMapPropagator(m.Propagator)
+		if m.LoggerProvider != nil {
+			global.SetLoggerProvider(m.LoggerProvider)
+		}
+		if m.MeterProvider != nil {
+			otel.SetMeterProvider(m.MeterProvider)
+		}
+	})
+}
 
-	// Note: BatchSpanProcessor processes spans in batches before they are exported. Preferred processor.
-	// SimpleSpanProcessor processes & exports each span as it is created. Pros: no risk of losing a batch. Cons: app's execution is blocked until each span is processed and sent over the network
-	processor := sdktrace.NewBatchSpanProcessor(exporter, sdktrace.WithBatchTimeout(cfg.BatchTimeout)) // create a batch span processor explicitly
-	traceProvider := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(cfg.Sampler),
-		sdktrace.WithSpanProcessor(processor), // OR directly use: sdktrace.WithBatcher(exporter), if processor needn't be returned from the function
-		sdktrace.WithResource(resources),
-	)
+// Shutdown flushes any spans still buffered in the processor, exports them and stops the TracerProvider.
+// It should be called once before the application exits (Eg: at the end of main() or in a signal handler)
+// so that the last batch of spans isn't lost. The returned error reports whether the final export failed.
+//
+// ctx bounds how long to wait for in-flight spans to be exported, Eg: context.WithTimeout(ctx, 5*time.Second).
+// Shutdown is safe to call multiple times; calls after the first one are no-ops returning the first call's result.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.shutdownOnce.Do(func() {
+		var errs []error
+		// Shutting down the TracerProvider also shuts down (i.e. drains) every span processor registered on it,
+		// i.e. all of Processors: they mustn't be shut down again
+		if err := m.TracerProvider.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("could not shutdown Tracer Provider: %s", err))
+		}
+		if m.LoggerProvider != nil {
+			if err := m.LoggerProvider.Shutdown(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("could not shutdown Logger Provider: %s", err))
+			}
+		}
+		// Shutting down the MeterProvider flushes (i.e. exports) the metrics collected so far
+		if m.MeterProvider != nil {
+			if err := m.MeterProvider.Shutdown(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("could not shutdown Meter Provider: %s", err))
+			}
+		}
+		// Closed last, once the exporters using them are shut down
+		for _, conn := range m.conns {
+			if err := conn.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("could not close connection to %s: %s", conn.Target(), err))
+			}
+		}
+		m.shutdownErr = errors.Join(errs...)
+	})
+	return m.shutdownErr
+}
 
-	// Specifications for instrumentation: https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/trace/api.md
-	return &Manager{traceProvider, processor, new(propagation.TraceContext)}, nil
+// ForceFlush synchronously exports all spans currently buffered in the processor, Eg: before a deliberate panic
+// or a deployment drain. Unlike Shutdown, it does not stop the TracerProvider; tracing continues as usual afterwards.
+// ctx can be used to cancel the flush; the underlying flush error is returned unchanged.
+//
+// In tests, call ForceFlush after the operation under test and before asserting on the exported spans,
+// since the batch processor otherwise exports them asynchronously (after BatchTimeout).
+func (m *Manager) ForceFlush(ctx context.Context) error {
+	return m.TracerProvider.ForceFlush(ctx)
+}
+
+// Endpoint sentinels returned by Manager.Endpoint for the exporters that don't send traces over the network.
+const (
+	// EndpointStdout - traces are written to std output (or Config.DebugOutput), see BackendStdout.
+	EndpointStdout = "stdout"
+	// EndpointInMemory - traces are kept in memory, see BackendInMemory.
+	EndpointInMemory = "memory"
+)
+
+// Endpoint returns the endpoint the manager sends traces to, as resolved by New (i.e. after the environment fallback,
+// defaults and normalization, Eg: "localhost:4317"). If multiple exporters are configured, it is the endpoint of the one
+// described by the exporter fields of Config. It returns EndpointStdout or EndpointInMemory if traces aren't sent
+// over the network, the file path with BackendFile, or "" for a disabled manager.
+func (m *Manager) Endpoint() string {
+	if len(m.exporterConfigs) == 0 {
+		return ""
+	}
+	return m.exporterConfigs[0].resolvedEndpoint()
+}
+
+// Backend returns the backend the manager sends traces to, as resolved by New (Eg: BackendStdout if
+// Config.DebugOutput is set). See Endpoint for managers with multiple exporters.
+func (m *Manager) Backend() Backend {
+	if len(m.exporterConfigs) == 0 {
+		return BackendOTLP
+	}
+	return m.exporterConfigs[0].backend()
+}
+
+// Transport returns the protocol used to send traces to Endpoint. Only meaningful with BackendOTLP (see Backend).
+func (m *Manager) Transport() Transport {
+	if len(m.exporterConfigs) == 0 {
+		return TransportGRPC
+	}
+	return m.exporterConfigs[0].Transport
 }
@@ -0,0 +1,160 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+)
+
+// shutdownCountingProcessor counts the calls to Shutdown, failing the calls after the first one.
+type shutdownCountingProcessor struct {
+	noopSpanProcessor
+	shutdowns int
+}
+
+func (p *shutdownCountingProcessor) Shutdown(context.Context) error {
+	p.shutdowns++
+	if p.shutdowns > 1 {
+		return errors.New("already shut down")
+	}
+	return nil
+}
+
+func TestShutdownShutsDownSpanProcessorsOnce(t *testing.T) {
+	ctx := context.Background()
+	processor := &shutdownCountingProcessor{}
+	m, err := NewInMemory(ctx, Config{Logger: NopLogger, SpanProcessors: []sdktrace.SpanProcessor{processor}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown() = %s, want nil", err)
+	}
+	if processor.shutdowns != 1 {
+		t.Errorf("processor shut down %d times, want 1", processor.shutdowns)
+	}
+}
+
+func TestNewHonorsContextDeadline(t *testing.T) {
+	// An endpoint nothing listens on, as the listener is closed right away
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoint := listener.Addr().String()
+	listener.Close()
+
+	const deadline = 200 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), deadline)
+	defer cancel()
+	start := time.Now()
+	m, err := New(ctx, Config{
+		Logger:   NopLogger,
+		Endpoint: endpoint,
+		Insecure: true,
+		// Block until connected, so that New waits for the (unreachable) collector
+		GRPCDialOptions: []grpc.DialOption{grpc.WithBlock()},
+	})
+	elapsed := time.Since(start)
+
+	if err == nil {
+		m.Shutdown(context.Background())
+		t.Fatal("New() succeeded, want an error connecting to the unreachable endpoint")
+	}
+	// The deadline of ctx applies, rather than ConnectTimeout (DefaultConnectTimeout)
+	if elapsed > deadline+time.Second {
+		t.Errorf("New() failed after %s, want within the %s deadline", elapsed, deadline)
+	}
+}
+
+func TestServiceNamePrecedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        string
+		attributes map[string]string
+		want       string
+	}{
+		{name: "attribute wins over OTEL_SERVICE_NAME", env: "env-service", attributes: map[string]string{"service.name": "attribute-service"}, want: "attribute-service"},
+		{name: "OTEL_SERVICE_NAME without attribute", env: "env-service", want: "env-service"},
+		{name: "attribute without OTEL_SERVICE_NAME", attributes: map[string]string{"service.name": "attribute-service"}, want: "attribute-service"},
+		{name: "DefaultServiceName without either", want: DefaultServiceName},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// An empty OTEL_SERVICE_NAME is ignored, as if unset
+			t.Setenv("OTEL_SERVICE_NAME", tt.env)
+			ctx := context.Background()
+			m, err := NewInMemory(ctx, Config{Logger: NopLogger, Attributes: tt.attributes})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer m.Shutdown(ctx)
+
+			_, span := m.Start(ctx, "operation")
+			span.End()
+			spans := m.RecordedSpans(ctx)
+			if len(spans) != 1 {
+				t.Fatalf("got %d spans, want 1", len(spans))
+			}
+			if got, _ := spans[0].Resource().Set().Value(serviceNameKey); got.AsString() != tt.want {
+				t.Errorf("service.name = %q, want %q", got.AsString(), tt.want)
+			}
+		})
+	}
+}
+
+// openFiles returns the number of files open by the process, skipping the test if unknown.
+func openFiles(t *testing.T) int {
+	t.Helper()
+	fds, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skipf("can't count open files: %s", err)
+	}
+	return len(fds)
+}
+
+func TestNewShutsDownExportersOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	before := openFiles(t)
+
+	_, err := New(context.Background(), Config{
+		Logger:     NopLogger,
+		FileOutput: filepath.Join(dir, "traces.jsonl"),
+		// Fails to open the file, after the main exporter opened its own
+		Exporters: []ExporterConfig{{FileOutput: filepath.Join(dir, "missing", "traces.jsonl")}},
+	})
+	if err == nil {
+		t.Fatal("New() succeeded, want an error opening the missing directory's file")
+	}
+	if after := openFiles(t); after != before {
+		t.Errorf("%d files open after New failed, want %d (the main exporter's file must be closed)", after, before)
+	}
+}
+
+func TestCloneRejectsSharedFileOutput(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	m, err := New(ctx, Config{Logger: NopLogger, Backend: BackendFile, FileOutput: filepath.Join(dir, "traces.jsonl")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Shutdown(ctx)
+
+	if clone, err := m.Clone(ctx, Config{InstrumentationVersion: "v2"}); err == nil {
+		clone.Shutdown(ctx)
+		t.Fatal("Clone() without a FileOutput override = nil error, want an error")
+	}
+	clone, err := m.Clone(ctx, Config{FileOutput: filepath.Join(dir, "clone.jsonl")})
+	if err != nil {
+		t.Fatalf("Clone() = %s", err)
+	}
+	clone.Shutdown(ctx)
+}
@@ -0,0 +1,243 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns an interceptor tracing every unary RPC by a server span, child of the remote span
+// whose trace context is extracted from the incoming metadata (using the manager's Propagator).
+// The span is attached to the handler's ctx and records the gRPC status code; RPCs failing with an error mark it as failed.
+// Eg: grpc.NewServer(grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor(manager)))
+func UnaryServerInterceptor(manager *Manager) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := startServerSpan(ctx, manager, info.FullMethod)
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+		endRPCSpan(span, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor tracing every streaming RPC by a server span, as UnaryServerInterceptor.
+// The span covers the whole stream and is attached to the stream's Context().
+// Eg: grpc.NewServer(grpc.ChainStreamInterceptor(tracing.StreamServerInterceptor(manager)))
+func StreamServerInterceptor(manager *Manager) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startServerSpan(ss.Context(), manager, info.FullMethod)
+		defer span.End()
+
+		err := handler(srv, &tracedServerStream{ServerStream: ss, ctx: ctx})
+		endRPCSpan(span, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor returns an interceptor tracing every outgoing unary RPC by a client span (child of the span in ctx,
+// if any, and subject to the manager's sampler), whose trace context is injected into the outgoing metadata
+// (using the manager's Propagator) so that the server can continue the trace.
+// Eg: grpc.NewClient(target, grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor(manager)))
+func UnaryClientInterceptor(manager *Manager) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := startClientSpan(ctx, manager, method)
+		defer span.End()
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		endRPCSpan(span, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns an interceptor tracing every outgoing streaming RPC by a client span, as UnaryClientInterceptor.
+// The span ends once the stream is finished, i.e. when receiving the final message, io.EOF or an error from RecvMsg,
+// an error from SendMsg, Header or CloseSend, or once the stream's ctx is done (Eg: cancelled).
+// Eg: grpc.NewClient(target, grpc.WithChainStreamInterceptor(tracing.StreamClientInterceptor(manager)))
+func StreamClientInterceptor(manager *Manager) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, span := startClientSpan(ctx, manager, method)
+
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			endRPCSpan(span, err)
+			span.End()
+			return nil, err
+		}
+		traced := &tracedClientStream{ClientStream: stream, span: span, serverStreams: desc.ServerStreams, done: make(chan struct{})}
+		// A stream abandoned by cancelling its context (without reading it until the end) must still end its span
+		go func() {
+			select {
+			case <-ctx.Done():
+				traced.end(status.FromContextError(ctx.Err()).Err())
+			case <-traced.done:
+			}
+		}()
+		return traced, nil
+	}
+}
+
+// UnaryServerInterceptor is the Manager method equivalent of UnaryServerInterceptor, using the manager's tracer & propagator.
+// Eg: grpc.NewServer(grpc.ChainUnaryInterceptor(manager.UnaryServerInterceptor()))
+func (m *Manager) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return UnaryServerInterceptor(m)
+}
+
+// StreamServerInterceptor is the Manager method equivalent of StreamServerInterceptor.
+func (m *Manager) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return StreamServerInterceptor(m)
+}
+
+// UnaryClientInterceptor is the Manager method equivalent of UnaryClientInterceptor.
+// Eg: grpc.NewClient(target, grpc.WithChainUnaryInterceptor(manager.UnaryClientInterceptor()))
+func (m *Manager) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return UnaryClientInterceptor(m)
+}
+
+// StreamClientInterceptor is the Manager method equivalent of StreamClientInterceptor.
+func (m *Manager) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return StreamClientInterceptor(m)
+}
+
+// startServerSpan starts the server span of the RPC fullMethod (Eg: "/package.Service/Method").
+func startServerSpan(ctx context.Context, manager *Manager, fullMethod string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = manager.Extract(ctx, metadataCarrier(md))
+
+	name, attrs := rpcSpanNameAndAttributes(fullMethod)
+	return manager.Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+}
+
+// startClientSpan starts the client span of the RPC fullMethod, and injects its trace context into the outgoing metadata.
+func startClientSpan(ctx context.Context, manager *Manager, fullMethod string) (context.Context, trace.Span) {
+	name, attrs := rpcSpanNameAndAttributes(fullMethod)
+	ctx, span := manager.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+
+	// Copy the metadata, as the metadata in ctx must not be modified
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	manager.Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+// rpcSpanNameAndAttributes returns the span name (Eg: "package.Service/Method") and attributes of the RPC fullMethod.
+func rpcSpanNameAndAttributes(fullMethod string) (string, []attribute.KeyValue) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	attrs := []attribute.KeyValue{semconv.RPCSystemGRPC}
+	if service, method, ok := strings.Cut(name, "/"); ok {
+		attrs = append(attrs, semconv.RPCService(service), semconv.RPCMethod(method))
+	}
+	return name, attrs
+}
+
+// endRPCSpan records the gRPC status of err (nil meaning OK) on span.
+func endRPCSpan(span trace.Span, err error) {
+	s, _ := status.FromError(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(s.Code())))
+	if err != nil {
+		span.SetStatus(codes.Error, s.Message())
+	}
+}
+
+// tracedServerStream overrides the context of a ServerStream with the one containing the RPC's span.
+type tracedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tracedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// tracedClientStream ends the span of a client stream once the stream is finished.
+type tracedClientStream struct {
+	grpc.ClientStream
+	span          trace.Span
+	serverStreams bool // whether the server sends a stream of messages (otherwise, a single one)
+
+	endOnce sync.Once
+	done    chan struct{} // closed once the span is ended
+}
+
+func (s *tracedClientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	// io.EOF means the stream was terminated by the server: its status is only returned by RecvMsg
+	if err != nil && !errors.Is(err, io.EOF) {
+		s.end(err)
+	}
+	return err
+}
+
+func (s *tracedClientStream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	if err != nil {
+		s.end(err)
+	}
+	return md, err
+}
+
+func (s *tracedClientStream) CloseSend() error {
+	err := s.ClientStream.CloseSend()
+	if err != nil {
+		s.end(err)
+	}
+	return err
+}
+
+func (s *tracedClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case errors.Is(err, io.EOF):
+		s.end(nil)
+	case err != nil:
+		s.end(err)
+	case !s.serverStreams:
+		s.end(nil)
+	}
+	return err
+}
+
+func (s *tracedClientStream) end(err error) {
+	s.endOnce.Do(func() {
+		endRPCSpan(s.span, err)
+		s.span.End()
+		close(s.done)
+	})
+}
+
+// metadataCarrier adapts gRPC metadata to a propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestStreamClientInterceptorEndsCancelledStreamSpan(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	defer server.Stop()
+
+	ctx := context.Background()
+	m, err := NewInMemory(ctx, Config{Logger: NopLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := grpc.NewClient(listener.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainStreamInterceptor(m.StreamClientInterceptor()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Watch streams the serving status until cancelled: the stream is abandoned after its first message
+	streamCtx, cancel := context.WithCancel(ctx)
+	stream, err := healthpb.NewHealthClient(conn).Watch(streamCtx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	var spans []sdktrace.ReadOnlySpan
+	for deadline := time.Now().Add(5 * time.Second); len(spans) == 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+		spans = m.RecordedSpans(ctx)
+	}
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	attrs := attribute.NewSet(spans[0].Attributes()...)
+	code, _ := attrs.Value(semconv.RPCGRPCStatusCodeKey)
+	if code.AsInt64() != int64(codes.Canceled) {
+		t.Errorf("status code attribute = %d, want %d (Canceled)", code.AsInt64(), codes.Canceled)
+	}
+}
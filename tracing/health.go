@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"google.golang.org/grpc/connectivity"
+)
+
+// Healthy checks that the collector(s)/remote backend(s) the manager exports to are reachable, Eg: for a readiness
+// probe tied to trace delivery after a collector outage. ctx bounds the check, Eg: context.WithTimeout(ctx, time.Second).
+//
+// It checks the state of the gRPC connection of each OTLP/gRPC exporter, Config.GRPCConn or the one dialed by New
+// (asking it to reconnect if idle), and opens (and immediately closes) a TCP connection to the endpoint of the
+// other exporters (OTLP/HTTP, BackendZipkin). Exporters without a remote connection (BackendStdout,
+// BackendInMemory, BackendFile) and BackendJaeger (using connectionless UDP) are always considered healthy.
+// Note: a reachable collector may still reject exports (Eg: authentication failures), see Config.ErrorHandler.
+func (m *Manager) Healthy(ctx context.Context) error {
+	var errs []error
+	for _, cfg := range m.exporterConfigs {
+		if err := checkExporter(ctx, cfg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkExporter checks that the remote endpoint of the exporter described by cfg (if any) is reachable.
+func checkExporter(ctx context.Context, cfg ExporterConfig) error {
+	if cfg.backend() == BackendOTLP && cfg.Transport == TransportGRPC && cfg.GRPCConn != nil {
+		switch state := cfg.GRPCConn.GetState(); state {
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			return fmt.Errorf("trace collector connection to %s is down: %s", cfg.GRPCConn.Target(), state)
+		case connectivity.Idle:
+			cfg.GRPCConn.Connect()
+		}
+		return nil
+	}
+
+	address, err := exporterAddress(cfg)
+	if err != nil || address == "" {
+		return err
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("could not reach trace collector at %s: %s", address, err)
+	}
+	return conn.Close()
+}
+
+// exporterAddress returns the host:port the exporter described by cfg connects to over TCP,
+// or "" if it doesn't connect to a remote endpoint over TCP.
+func exporterAddress(cfg ExporterConfig) (string, error) {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = defaultEndpoint(cfg)
+	}
+	switch cfg.backend() {
+	case BackendOTLP:
+		cfg, err := normalizeOTLPEndpoint(cfg)
+		if err != nil {
+			return "", err
+		}
+		// Eg: "localhost:4318/v1/traces" -> "localhost:4318"
+		hostPort, _, _ := strings.Cut(cfg.Endpoint, "/")
+		return withDefaultPort(hostPort, cfg.Insecure), nil
+	case BackendZipkin:
+		u, err := url.Parse(cfg.Endpoint)
+		if err != nil || u.Host == "" {
+			return "", fmt.Errorf("invalid Zipkin collector URL %q", cfg.Endpoint)
+		}
+		return withDefaultPort(u.Host, u.Scheme == "http"), nil
+	default:
+		return "", nil
+	}
+}
+
+// withDefaultPort returns hostPort, with the default HTTP (if insecure) or HTTPS port if it doesn't have a port.
+func withDefaultPort(hostPort string, insecure bool) string {
+	if _, _, err := net.SplitHostPort(hostPort); err == nil {
+		return hostPort
+	}
+	if insecure {
+		return net.JoinHostPort(hostPort, "80")
+	}
+	return net.JoinHostPort(hostPort, "443")
+}
@@ -0,0 +1,182 @@
+package tracing
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HTTPMiddleware wraps next so that every incoming request is traced by a server span, child of the remote span
+// whose trace context is extracted from the request headers (using the manager's Propagator, W3C TraceContext by default).
+// The span is attached to the request context (Eg: retrieve it in next with trace.SpanFromContext(r.Context()))
+// and ends once next returns, recording the response status code. 5xx responses mark the span as failed.
+// The span is named after the method and, if next is (or wraps) an http.ServeMux, the matched route
+// (Eg: "GET /users/{id}"), and has the standard HTTP semantic convention attributes, except url.query: the query
+// string often carries secrets (Eg: API keys, tokens), so it isn't recorded.
+// Eg: http.ListenAndServe(":8080", tracing.HTTPMiddleware(manager, mux))
+func HTTPMiddleware(manager *Manager, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := manager.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		attrs := []attribute.KeyValue{
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			semconv.URLScheme(scheme),
+			semconv.URLPath(r.URL.Path),
+			semconv.NetworkProtocolVersion(fmt.Sprintf("%d.%d", r.ProtoMajor, r.ProtoMinor)),
+		}
+		if host, port, err := net.SplitHostPort(r.Host); err == nil {
+			attrs = append(attrs, semconv.ServerAddress(host))
+			if port, err := strconv.Atoi(port); err == nil {
+				attrs = append(attrs, semconv.ServerPort(port))
+			}
+		} else if r.Host != "" {
+			attrs = append(attrs, semconv.ServerAddress(r.Host))
+		}
+		if userAgent := r.UserAgent(); userAgent != "" {
+			attrs = append(attrs, semconv.UserAgentOriginal(userAgent))
+		}
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			attrs = append(attrs, semconv.ClientAddress(host))
+		}
+		// The span is named after the method (and later the route) only, as the URL path would make span names high-cardinality
+		ctx, span := manager.Start(ctx, r.Method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+		defer span.End()
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		req := r.WithContext(ctx)
+		if _, ok := w.(http.Hijacker); ok {
+			next.ServeHTTP(hijackerStatusRecorder{recorder}, req)
+		} else {
+			next.ServeHTTP(recorder, req)
+		}
+
+		// ServeMux sets the pattern matching the request (Eg: "GET example.com/users/{id}") while routing it
+		if route := httpRoute(req.Pattern); route != "" {
+			span.SetName(r.Method + " " + route)
+			span.SetAttributes(semconv.HTTPRoute(route))
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(recorder.status))
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.status))
+		}
+	})
+}
+
+// HTTPMiddleware is the Manager method equivalent of HTTPMiddleware.
+// Eg: http.ListenAndServe(":8080", manager.HTTPMiddleware(mux))
+func (m *Manager) HTTPMiddleware(next http.Handler) http.Handler {
+	return HTTPMiddleware(m, next)
+}
+
+// httpRoute returns the path of the ServeMux pattern (Eg: "GET example.com/users/{id}" -> "/users/{id}").
+func httpRoute(pattern string) string {
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		pattern = path
+	}
+	if i := strings.Index(pattern, "/"); i > 0 {
+		pattern = pattern[i:]
+	}
+	return pattern
+}
+
+// NewTracingTransport returns a RoundTripper tracing every outgoing request by a client span (child of the span in the
+// request context, if any), whose trace context is injected into the request headers (using the manager's Propagator)
+// so that the server can continue the trace. The response status code is recorded on the span, and errors
+// (Eg: connection failures) are recorded as span events. 4xx and 5xx responses mark the span as failed.
+// If wrapped is nil, http.DefaultTransport is used.
+// Eg: http.Client{Transport: tracing.NewTracingTransport(manager, http.DefaultTransport)}
+func NewTracingTransport(manager *Manager, wrapped http.RoundTripper) http.RoundTripper {
+	if wrapped == nil {
+		wrapped = http.DefaultTransport
+	}
+	return &tracingTransport{manager: manager, wrapped: wrapped}
+}
+
+// tracingTransport traces the requests sent by the wrapped RoundTripper.
+type tracingTransport struct {
+	manager *Manager
+	wrapped http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := t.manager.Start(req.Context(), req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.URLFull(req.URL.Redacted()),
+			semconv.ServerAddress(req.URL.Hostname()),
+		),
+	)
+	defer span.End()
+
+	// A RoundTripper must not modify the request, so the headers are injected into a copy
+	req = req.Clone(ctx)
+	t.manager.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.wrapped.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
+
+// statusRecorder records the status code written to the wrapped ResponseWriter.
+// The status defaults to 200, the status implicitly written by the first call to Write.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush flushes the wrapped ResponseWriter, if it supports it, so that handlers asserting http.Flusher
+// (Eg: streaming server-sent events) keep working.
+func (w *statusRecorder) Flush() {
+	w.wroteHeader = true
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the wrapped ResponseWriter (Eg: to flush or hijack the connection).
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// hijackerStatusRecorder is a statusRecorder also implementing http.Hijacker, used only when the wrapped
+// ResponseWriter does (Eg: not for HTTP/2), so that handlers asserting it (Eg: websocket upgrades) keep working.
+type hijackerStatusRecorder struct {
+	*statusRecorder
+}
+
+func (w hijackerStatusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestHTTPMiddlewareKeepsOptionalInterfaces(t *testing.T) {
+	m, err := NewInMemory(context.Background(), Config{Logger: NopLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var flushed, hijacked bool
+	handler := m.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, hijacked = w.(http.Hijacker)
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+			flushed = true
+		}
+	}))
+
+	// httptest.ResponseRecorder implements http.Flusher but not http.Hijacker
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !flushed || !recorder.Flushed {
+		t.Error("the wrapped ResponseWriter wasn't flushed")
+	}
+	if hijacked {
+		t.Error("the ResponseWriter implements http.Hijacker although the wrapped one doesn't")
+	}
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	mu.Lock()
+	defer mu.Unlock()
+	if !hijacked {
+		t.Error("the ResponseWriter doesn't implement http.Hijacker although the wrapped one does")
+	}
+}
@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// NewInMemory creates a Manager recording all spans in memory (see BackendInMemory), for unit testing tracing code
+// without a collector. Spans are exported synchronously as they end (ProcessorSimple) and every root span is sampled,
+// so that they can be asserted on right away with RecordedSpans.
+// The other fields of cfg (Eg: Attributes, Propagators, SpanProcessors) apply as with New; Backend, DebugOutput
+// and ProcessorMode are overridden.
+func NewInMemory(ctx context.Context, cfg Config) (*Manager, error) {
+	cfg.Backend = BackendInMemory
+	cfg.DebugOutput = nil
+	cfg.ProcessorMode = ProcessorSimple
+	if cfg.Sampler == nil && cfg.SampleRatio == 0 {
+		cfg.Sampler = sdktrace.AlwaysSample()
+	}
+	return New(ctx, cfg)
+}
+
+// RecordedSpans returns the spans recorded so far when using BackendInMemory (Eg: see NewInMemory), in the order
+// they ended, after flushing the spans still buffered in the processor. It returns nil for other backends.
+// Eg: assert on span.Name(), span.Attributes(), span.Status(), span.Parent()
+func (m *Manager) RecordedSpans(ctx context.Context) []sdktrace.ReadOnlySpan {
+	if m.InMemoryExporter == nil {
+		return nil
+	}
+	_ = m.ForceFlush(ctx)
+	return m.InMemoryExporter.GetSpans().Snapshots()
+}
+
+// ResetRecordedSpans discards the spans recorded so far when using BackendInMemory, Eg: between test cases.
+func (m *Manager) ResetRecordedSpans() {
+	if m.InMemoryExporter != nil {
+		m.InMemoryExporter.Reset()
+	}
+}
@@ -0,0 +1,35 @@
+package tracing
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+// Logger is the minimal logging interface the package writes its internal logs to (Eg: startup info, ignored
+// configuration, failed exports). *logrus.Logger and *logrus.Entry satisfy it; other loggers (Eg: zap, slog) need a small adapter.
+type Logger interface {
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// NopLogger discards all the logs, Eg: to silence the package entirely (see Config.Logger).
+var NopLogger Logger = nopLogger{}
+
+type nopLogger struct{}
+
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Warnf(string, ...interface{})  {}
+func (nopLogger) Errorf(string, ...interface{}) {}
+
+// logger returns the Logger configured on c, or the global logrus logger if unset.
+func (c Config) logger() Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return log.StandardLogger()
+}
+
+// log returns the Logger the manager was created with, or the global logrus logger if unset.
+func (m *Manager) log() Logger {
+	return Config{Logger: m.logger}.logger()
+}
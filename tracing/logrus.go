@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Keys of the log fields correlating log entries with traces (see LogrusFields).
+const (
+	TraceIDField    = "trace_id"
+	SpanIDField     = "span_id"
+	TraceFlagsField = "trace_flags"
+)
+
+// LogrusFields returns the log fields correlating a log entry with the current span in ctx: its trace ID, span ID and
+// trace flags (Eg: "01" if sampled), hex-encoded. It returns empty fields if ctx doesn't carry a (valid) span context.
+// Eg: log.WithContext(ctx).WithFields(tracing.LogrusFields(ctx)).Info("Processing order")
+func LogrusFields(ctx context.Context) log.Fields {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return log.Fields{}
+	}
+	return log.Fields{
+		TraceIDField:    spanContext.TraceID().String(),
+		SpanIDField:     spanContext.SpanID().String(),
+		TraceFlagsField: spanContext.TraceFlags().String(),
+	}
+}
+
+// TraceHook is a logrus hook adding the trace correlation fields (see LogrusFields) to every log entry
+// carrying a context with an active span, so that call sites needn't add them manually.
+// Entries without a context (or whose context doesn't carry a span, Eg: in background goroutines) are left untouched.
+// Eg: logger.AddHook(&tracing.TraceHook{Manager: manager}), then logger.WithContext(ctx).Info("Processing order")
+type TraceHook struct {
+	Manager *Manager
+}
+
+var _ log.Hook = (*TraceHook)(nil)
+
+// Levels returns all the log levels, i.e. the hook fires for every entry.
+func (h *TraceHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire adds the trace correlation fields to entry, if its context carries an active span.
+func (h *TraceHook) Fire(entry *log.Entry) error {
+	if entry.Context == nil || !h.Manager.SpanContextFromContext(entry.Context).IsValid() {
+		return nil
+	}
+	for k, v := range LogrusFields(entry.Context) {
+		entry.Data[k] = v
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package tracing
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"google.golang.org/grpc/credentials"
+)
+
+// newLogExporter creates the log exporter sending logs to the same collector as the trace exporter described by cfg,
+// with the same connection settings (Eg: TLS, headers, compression). With TransportGRPC, it shares cfg.GRPCConn, the
+// trace exporter's connection (see dialExporterConn), without closing it. Only BackendOTLP & DebugOutput are supported.
+func newLogExporter(ctx context.Context, cfg ExporterConfig) (sdklog.Exporter, error) {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = defaultEndpoint(cfg)
+	}
+
+	switch cfg.backend() {
+	case BackendOTLP:
+		var err error
+		if cfg.GRPCConn == nil || cfg.Transport != TransportGRPC {
+			if cfg, err = normalizeOTLPEndpoint(cfg); err != nil {
+				return nil, err
+			}
+		}
+		tlsConfig, err := newTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		switch cfg.Transport {
+		case TransportGRPC:
+			return newGRPCLogExporter(ctx, cfg, tlsConfig)
+		case TransportHTTP:
+			return newHTTPLogExporter(ctx, cfg, tlsConfig)
+		default:
+			return nil, fmt.Errorf("unsupported transport: %d", cfg.Transport)
+		}
+	case BackendStdout:
+		output := cfg.DebugOutput
+		if output == nil {
+			output = os.Stdout
+		}
+		options := []stdoutlog.Option{stdoutlog.WithWriter(output)}
+		if cfg.DebugFormat == DebugFormatPretty {
+			options = append(options, stdoutlog.WithPrettyPrint())
+		}
+		return stdoutlog.New(options...)
+	default:
+		return nil, errors.New("logs can only be exported with BackendOTLP or DebugOutput")
+	}
+}
+
+// newGRPCLogExporter creates an OTLP log exporter sending logs over gRPC (see newGRPCClient).
+func newGRPCLogExporter(ctx context.Context, cfg ExporterConfig, tlsConfig *tls.Config) (sdklog.Exporter, error) {
+	secureOption := otlploggrpc.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, ""))
+	if tlsConfig != nil {
+		secureOption = otlploggrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig))
+	} else if cfg.Insecure {
+		secureOption = otlploggrpc.WithInsecure()
+	}
+	options := []otlploggrpc.Option{secureOption, otlploggrpc.WithEndpoint(cfg.Endpoint)}
+	if len(cfg.GRPCDialOptions) > 0 {
+		options = append(options, otlploggrpc.WithDialOption(cfg.GRPCDialOptions...))
+	}
+	if cfg.GRPCConn != nil {
+		options = append(options, otlploggrpc.WithGRPCConn(cfg.GRPCConn))
+	}
+	if len(cfg.Headers) > 0 {
+		options = append(options, otlploggrpc.WithHeaders(cfg.Headers))
+	}
+	if cfg.Compression == CompressionGzip {
+		options = append(options, otlploggrpc.WithCompressor("gzip"))
+	}
+	if cfg.ExportTimeout > 0 {
+		options = append(options, otlploggrpc.WithTimeout(cfg.ExportTimeout))
+	}
+	if cfg.RetryConfig != nil {
+		retry := cfg.RetryConfig.withDefaults()
+		options = append(options, otlploggrpc.WithRetry(otlploggrpc.RetryConfig{
+			Enabled:         retry.Enabled,
+			InitialInterval: retry.InitialInterval,
+			MaxInterval:     retry.MaxInterval,
+			MaxElapsedTime:  retry.MaxElapsedTime,
+		}))
+	}
+	return otlploggrpc.New(ctx, options...)
+}
+
+// newHTTPLogExporter creates an OTLP log exporter sending logs over HTTP (see newHTTPClient).
+// A custom trace URL path ending with /v1/traces (Eg: /otlp/v1/traces) is mapped to the corresponding logs path (/otlp/v1/logs).
+func newHTTPLogExporter(ctx context.Context, cfg ExporterConfig, tlsConfig *tls.Config) (sdklog.Exporter, error) {
+	endpoint, urlPath, _ := strings.Cut(cfg.Endpoint, "/")
+	options := []otlploghttp.Option{otlploghttp.WithEndpoint(endpoint)}
+	if prefix, ok := strings.CutSuffix(urlPath, "v1/traces"); ok {
+		options = append(options, otlploghttp.WithURLPath("/"+prefix+"v1/logs"))
+	}
+	if tlsConfig != nil {
+		options = append(options, otlploghttp.WithTLSClientConfig(tlsConfig))
+	} else if cfg.Insecure {
+		options = append(options, otlploghttp.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		options = append(options, otlploghttp.WithHeaders(cfg.Headers))
+	}
+	if cfg.Compression == CompressionGzip {
+		options = append(options, otlploghttp.WithCompression(otlploghttp.GzipCompression))
+	}
+	if cfg.ExportTimeout > 0 {
+		options = append(options, otlploghttp.WithTimeout(cfg.ExportTimeout))
+	}
+	if cfg.RetryConfig != nil {
+		retry := cfg.RetryConfig.withDefaults()
+		options = append(options, otlploghttp.WithRetry(otlploghttp.RetryConfig{
+			Enabled:         retry.Enabled,
+			InitialInterval: retry.InitialInterval,
+			MaxInterval:     retry.MaxInterval,
+			MaxElapsedTime:  retry.MaxElapsedTime,
+		}))
+	}
+	return otlploghttp.New(ctx, options...)
+}
+
+// newLoggerProvider creates the LoggerProvider exporting logs to exporter in batches, sharing the traces' resource.
+func newLoggerProvider(exporter sdklog.Exporter, resources *resource.Resource) *sdklog.LoggerProvider {
+	return sdklog.NewLoggerProvider(
+		sdklog.WithResource(resources),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+	)
+}
@@ -0,0 +1,143 @@
+package tracing
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"google.golang.org/grpc/credentials"
+)
+
+// newMetricExporter creates the metric exporter sending metrics to the same collector as the trace exporter described
+// by cfg, with the same connection settings (see newLogExporter). Only BackendOTLP & DebugOutput are supported.
+func newMetricExporter(ctx context.Context, cfg ExporterConfig) (sdkmetric.Exporter, error) {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = defaultEndpoint(cfg)
+	}
+
+	switch cfg.backend() {
+	case BackendOTLP:
+		var err error
+		if cfg.GRPCConn == nil || cfg.Transport != TransportGRPC {
+			if cfg, err = normalizeOTLPEndpoint(cfg); err != nil {
+				return nil, err
+			}
+		}
+		tlsConfig, err := newTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		switch cfg.Transport {
+		case TransportGRPC:
+			return newGRPCMetricExporter(ctx, cfg, tlsConfig)
+		case TransportHTTP:
+			return newHTTPMetricExporter(ctx, cfg, tlsConfig)
+		default:
+			return nil, fmt.Errorf("unsupported transport: %d", cfg.Transport)
+		}
+	case BackendStdout:
+		output := cfg.DebugOutput
+		if output == nil {
+			output = os.Stdout
+		}
+		options := []stdoutmetric.Option{stdoutmetric.WithWriter(output)}
+		if cfg.DebugFormat == DebugFormatPretty {
+			options = append(options, stdoutmetric.WithPrettyPrint())
+		}
+		return stdoutmetric.New(options...)
+	default:
+		return nil, errors.New("metrics can only be exported with BackendOTLP or DebugOutput")
+	}
+}
+
+// newGRPCMetricExporter creates an OTLP metric exporter sending metrics over gRPC (see newGRPCClient).
+func newGRPCMetricExporter(ctx context.Context, cfg ExporterConfig, tlsConfig *tls.Config) (sdkmetric.Exporter, error) {
+	secureOption := otlpmetricgrpc.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, ""))
+	if tlsConfig != nil {
+		secureOption = otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig))
+	} else if cfg.Insecure {
+		secureOption = otlpmetricgrpc.WithInsecure()
+	}
+	options := []otlpmetricgrpc.Option{secureOption, otlpmetricgrpc.WithEndpoint(cfg.Endpoint)}
+	if len(cfg.GRPCDialOptions) > 0 {
+		options = append(options, otlpmetricgrpc.WithDialOption(cfg.GRPCDialOptions...))
+	}
+	if cfg.GRPCConn != nil {
+		options = append(options, otlpmetricgrpc.WithGRPCConn(cfg.GRPCConn))
+	}
+	if len(cfg.Headers) > 0 {
+		options = append(options, otlpmetricgrpc.WithHeaders(cfg.Headers))
+	}
+	if cfg.Compression == CompressionGzip {
+		options = append(options, otlpmetricgrpc.WithCompressor("gzip"))
+	}
+	if cfg.ExportTimeout > 0 {
+		options = append(options, otlpmetricgrpc.WithTimeout(cfg.ExportTimeout))
+	}
+	if cfg.RetryConfig != nil {
+		retry := cfg.RetryConfig.withDefaults()
+		options = append(options, otlpmetricgrpc.WithRetry(otlpmetricgrpc.RetryConfig{
+			Enabled:         retry.Enabled,
+			InitialInterval: retry.InitialInterval,
+			MaxInterval:     retry.MaxInterval,
+			MaxElapsedTime:  retry.MaxElapsedTime,
+		}))
+	}
+	return otlpmetricgrpc.New(ctx, options...)
+}
+
+// newHTTPMetricExporter creates an OTLP metric exporter sending metrics over HTTP (see newHTTPClient).
+// A custom trace URL path ending with /v1/traces (Eg: /otlp/v1/traces) is mapped to the corresponding metrics path (/otlp/v1/metrics).
+func newHTTPMetricExporter(ctx context.Context, cfg ExporterConfig, tlsConfig *tls.Config) (sdkmetric.Exporter, error) {
+	endpoint, urlPath, _ := strings.Cut(cfg.Endpoint, "/")
+	options := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(endpoint)}
+	if prefix, ok := strings.CutSuffix(urlPath, "v1/traces"); ok {
+		options = append(options, otlpmetrichttp.WithURLPath("/"+prefix+"v1/metrics"))
+	}
+	if tlsConfig != nil {
+		options = append(options, otlpmetrichttp.WithTLSClientConfig(tlsConfig))
+	} else if cfg.Insecure {
+		options = append(options, otlpmetrichttp.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		options = append(options, otlpmetrichttp.WithHeaders(cfg.Headers))
+	}
+	if cfg.Compression == CompressionGzip {
+		options = append(options, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+	}
+	if cfg.ExportTimeout > 0 {
+		options = append(options, otlpmetrichttp.WithTimeout(cfg.ExportTimeout))
+	}
+	if cfg.RetryConfig != nil {
+		retry := cfg.RetryConfig.withDefaults()
+		options = append(options, otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig{
+			Enabled:         retry.Enabled,
+			InitialInterval: retry.InitialInterval,
+			MaxInterval:     retry.MaxInterval,
+			MaxElapsedTime:  retry.MaxElapsedTime,
+		}))
+	}
+	return otlpmetrichttp.New(ctx, options...)
+}
+
+// newMeterProvider creates the MeterProvider exporting metrics to exporter every interval (or the SDK default if unset),
+// sharing the traces' resource.
+func newMeterProvider(exporter sdkmetric.Exporter, interval time.Duration, resources *resource.Resource) *sdkmetric.MeterProvider {
+	var options []sdkmetric.PeriodicReaderOption
+	if interval > 0 {
+		options = append(options, sdkmetric.WithInterval(interval))
+	}
+	return sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(resources),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, options...)),
+	)
+}
@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// NewNoop creates a Manager that doesn't record, export or propagate any spans, Eg: to pass to components in tests
+// that don't care about tracing, instead of a nil *Manager. It is the manager New returns when Config.Disabled is set.
+// No network exporter is created and no background goroutines are started; all Manager methods are safe to call,
+// succeed instantly and have no observable side effects (Eg: RecordedSpans returns nil, Clone returns a no-op manager).
+func NewNoop() *Manager {
+	sampler := sdktrace.NeverSample()
+	return &Manager{
+		// A TracerProvider without any span processor that never samples is effectively a no-op
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler)),
+		Processor:      noopSpanProcessor{},
+		Processors:     []sdktrace.SpanProcessor{noopSpanProcessor{}},
+		// A composite of no propagators neither injects nor extracts anything
+		Propagator: propagation.NewCompositeTextMapPropagator(),
+		Exporter:   tracetest.NewNoopExporter(),
+		sampler:    sampler,
+		config:     Config{Disabled: true},
+	}
+}
+
+// noopSpanProcessor is a span processor that does nothing.
+type noopSpanProcessor struct{}
+
+func (noopSpanProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (noopSpanProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (noopSpanProcessor) Shutdown(context.Context) error { return nil }
+
+func (noopSpanProcessor) ForceFlush(context.Context) error { return nil }
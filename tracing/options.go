@@ -0,0 +1,143 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Option configures the Manager created by NewWithOptions.
+// Each Option sets the Config field of the same name (see Config for the details & defaults).
+type Option func(*options)
+
+// options collects the configuration set by Options.
+type options struct {
+	cfg Config
+}
+
+// NewWithOptions is the functional-option equivalent of New. Options are applied in order.
+// Eg: NewWithOptions(ctx, WithEndpoint("collector:4317"), WithAttributes(map[string]string{"service.name": "my-service"}))
+func NewWithOptions(ctx context.Context, opts ...Option) (*Manager, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return New(ctx, o.cfg)
+}
+
+// WithEndpoint sets the endpoint to send traces to.
+func WithEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.cfg.Endpoint = endpoint
+	}
+}
+
+// WithBackend sets the backend to send traces to.
+func WithBackend(backend Backend) Option {
+	return func(o *options) {
+		o.cfg.Backend = backend
+	}
+}
+
+// WithTransport sets the protocol used to send traces to the endpoint.
+func WithTransport(transport Transport) Option {
+	return func(o *options) {
+		o.cfg.Transport = transport
+	}
+}
+
+// WithInsecure disables client transport security for the exporter's connection.
+func WithInsecure() Option {
+	return func(o *options) {
+		o.cfg.Insecure = true
+	}
+}
+
+// WithHeaders adds headers to send on every export request. Multiple calls accumulate.
+func WithHeaders(headers map[string]string) Option {
+	return func(o *options) {
+		if o.cfg.Headers == nil {
+			o.cfg.Headers = make(map[string]string, len(headers))
+		}
+		for k, v := range headers {
+			o.cfg.Headers[k] = v
+		}
+	}
+}
+
+// WithAttributes adds resource attributes describing the thing sending the traces. Multiple calls accumulate.
+func WithAttributes(attributes map[string]string) Option {
+	return func(o *options) {
+		if o.cfg.Attributes == nil {
+			o.cfg.Attributes = make(map[string]string, len(attributes))
+		}
+		for k, v := range attributes {
+			o.cfg.Attributes[k] = v
+		}
+	}
+}
+
+// WithSampler sets the sampler.
+func WithSampler(sampler sdktrace.Sampler) Option {
+	return func(o *options) {
+		o.cfg.Sampler = sampler
+	}
+}
+
+// WithSampleRatio sets the ratio (between 0 and 1) of root spans to sample.
+func WithSampleRatio(ratio float64) Option {
+	return func(o *options) {
+		o.cfg.SampleRatio = ratio
+	}
+}
+
+// WithBatchTimeout sets the max duration for constructing a batch of spans.
+func WithBatchTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.cfg.BatchTimeout = timeout
+	}
+}
+
+// WithProcessorMode sets how spans are handed over to the exporter.
+func WithProcessorMode(mode ProcessorMode) Option {
+	return func(o *options) {
+		o.cfg.ProcessorMode = mode
+	}
+}
+
+// WithDebugOutput writes trace output to w instead of sending it to the endpoint.
+func WithDebugOutput(w io.Writer) Option {
+	return func(o *options) {
+		o.cfg.DebugOutput = w
+	}
+}
+
+// WithDebugFormat sets the format of the debug output.
+func WithDebugFormat(format DebugFormat) Option {
+	return func(o *options) {
+		o.cfg.DebugFormat = format
+	}
+}
+
+// WithSetGlobal sets the manager's TracerProvider & Propagator as the otel globals.
+func WithSetGlobal() Option {
+	return func(o *options) {
+		o.cfg.SetGlobal = true
+	}
+}
+
+// WithErrorHandler sets the handler called with the errors the OpenTelemetry SDK can't return (Eg: export failures).
+func WithErrorHandler(handler func(error)) Option {
+	return func(o *options) {
+		o.cfg.ErrorHandler = handler
+	}
+}
+
+// WithLogger sets the logger the package writes its internal logs to, Eg: WithLogger(NopLogger) to silence them.
+func WithLogger(logger Logger) Option {
+	return func(o *options) {
+		o.cfg.Logger = logger
+	}
+}
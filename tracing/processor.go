@@ -0,0 +1,156 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ProcessorMode - how spans are handed over to the exporter once they end.
+type ProcessorMode int
+
+const (
+	// ProcessorBatch exports spans in batches, asynchronously (default). Preferred processor.
+	// Pros: app's execution isn't blocked by exports. Cons: spans still buffered are lost if the process exits without Manager.Shutdown.
+	ProcessorBatch ProcessorMode = iota
+	// ProcessorSimple exports each span synchronously as soon as it ends.
+	// Pros: no risk of losing a batch (Eg: in serverless functions exiting right after handling a request), lower latency.
+	// Cons: app's execution is blocked until each span is sent over the network.
+	ProcessorSimple
+)
+
+// newProcessor creates the span processor handing spans over to exporter, as described by cfg.
+func newProcessor(exporter sdktrace.SpanExporter, cfg Config) (sdktrace.SpanProcessor, error) {
+	processor, err := newExportProcessor(exporter, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SpanNameSanitizer != nil {
+		processor = newTransformProcessor(processor, func(span sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+			return renamedSpan{ReadOnlySpan: span, name: cfg.SpanNameSanitizer(span.Name())}
+		})
+	}
+	if cfg.sampleErrors() {
+		processor = newErrorSamplingProcessor(processor)
+	}
+	return processor, nil
+}
+
+// newExportProcessor creates the batch or simple span processor handing spans over to exporter, as described by cfg.
+func newExportProcessor(exporter sdktrace.SpanExporter, cfg Config) (sdktrace.SpanProcessor, error) {
+	switch mode := cfg.ProcessorMode; mode {
+	case ProcessorBatch:
+		// The batch sizes are checked by Config.Validate
+		options := []sdktrace.BatchSpanProcessorOption{sdktrace.WithBatchTimeout(cfg.BatchTimeout)}
+		if cfg.MaxQueueSize > 0 {
+			options = append(options, sdktrace.WithMaxQueueSize(cfg.MaxQueueSize))
+		}
+		if cfg.MaxExportBatchSize > 0 {
+			options = append(options, sdktrace.WithMaxExportBatchSize(cfg.MaxExportBatchSize))
+		}
+		if cfg.ExportTimeout > 0 {
+			options = append(options, sdktrace.WithExportTimeout(cfg.ExportTimeout))
+		}
+		return sdktrace.NewBatchSpanProcessor(exporter, options...), nil
+	case ProcessorSimple:
+		return sdktrace.NewSimpleSpanProcessor(exporter), nil
+	default:
+		return nil, fmt.Errorf("unsupported processor mode: %d", mode)
+	}
+}
+
+// transformProcessor hands over the spans transformed by transform (Eg: sanitized) to next when they end.
+type transformProcessor struct {
+	next      sdktrace.SpanProcessor
+	transform func(sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan
+}
+
+func newTransformProcessor(next sdktrace.SpanProcessor, transform func(sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan) *transformProcessor {
+	return &transformProcessor{next: next, transform: transform}
+}
+
+func (p *transformProcessor) OnStart(ctx context.Context, span sdktrace.ReadWriteSpan) {
+	p.next.OnStart(ctx, span)
+}
+
+func (p *transformProcessor) OnEnd(span sdktrace.ReadOnlySpan) {
+	p.next.OnEnd(p.transform(span))
+}
+
+func (p *transformProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *transformProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// renamedSpan is a span exported under another name.
+type renamedSpan struct {
+	sdktrace.ReadOnlySpan
+	name string
+}
+
+func (s renamedSpan) Name() string {
+	return s.name
+}
+
+// attributesSpan is a span exported with other attributes.
+type attributesSpan struct {
+	sdktrace.ReadOnlySpan
+	attributes []attribute.KeyValue
+}
+
+func (s attributesSpan) Attributes() []attribute.KeyValue {
+	return s.attributes
+}
+
+// mapAttributes returns span with each of its attributes replaced by the one returned by f, if changed, or removed
+// unless keep. span itself is returned if f changes none of its attributes: the attributes are only copied on the
+// first change, as most spans have none to change.
+func mapAttributes(span sdktrace.ReadOnlySpan, f func(attr attribute.KeyValue) (mapped attribute.KeyValue, changed, keep bool)) sdktrace.ReadOnlySpan {
+	attrs := span.Attributes()
+	var result []attribute.KeyValue
+	for i, attr := range attrs {
+		mapped, changed, keep := f(attr)
+		if !changed && keep {
+			if result != nil {
+				result = append(result, attr)
+			}
+			continue
+		}
+		if result == nil {
+			result = append(make([]attribute.KeyValue, 0, len(attrs)), attrs[:i]...)
+		}
+		if keep {
+			result = append(result, mapped)
+		}
+	}
+	if result == nil {
+		return span
+	}
+	return attributesSpan{ReadOnlySpan: span, attributes: result}
+}
+
+// RegexpSanitizer returns a span name sanitizer (see Config.SpanNameSanitizer) replacing the matches of each pattern with
+// its replacement, in which $1, ${name}, etc. refer to the pattern's submatches (see regexp.Regexp.ReplaceAllString).
+// Patterns are applied in the lexical order of their expressions.
+// Eg: RegexpSanitizer(map[*regexp.Regexp]string{regexp.MustCompile(`/users/\d+`): "/users/{id}"})
+func RegexpSanitizer(patterns map[*regexp.Regexp]string) func(string) string {
+	sorted := make([]*regexp.Regexp, 0, len(patterns))
+	for pattern := range patterns {
+		sorted = append(sorted, pattern)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].String() < sorted[j].String() })
+
+	return func(name string) string {
+		for _, pattern := range sorted {
+			name = pattern.ReplaceAllString(name, patterns[pattern])
+		}
+		return name
+	}
+}
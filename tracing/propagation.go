@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// PropagationFormat - the format(s) used to propagate trace context to remote processes over the wire.
+// Formats can be combined to stay compatible with mixed fleets, in which case all of their headers are injected.
+// Eg: PropagationW3C | PropagationB3Single
+type PropagationFormat int
+
+const (
+	// PropagationW3C propagates W3C TraceContext headers (traceparent, tracestate) (default).
+	PropagationW3C PropagationFormat = 1 << iota
+	// PropagationB3Single propagates the B3 single header (b3: {traceId}-{spanId}-{flags}).
+	// Eg: used by Envoy sidecars, Istio meshes, etc.
+	PropagationB3Single
+	// PropagationB3Multi propagates the B3 multiple headers (x-b3-traceid, x-b3-spanid, x-b3-sampled, etc.).
+	PropagationB3Multi
+
+	propagationAll = PropagationW3C | PropagationB3Single | PropagationB3Multi
+)
+
+// newPropagator creates the (composite) propagator described by cfg.
+// cfg.Propagators, if set, takes precedence over cfg.PropagationFormat.
+func newPropagator(cfg Config) (propagation.TextMapPropagator, error) {
+	if len(cfg.Propagators) > 0 {
+		return newPropagatorFromNames(cfg.Propagators)
+	}
+	return newPropagatorFromFormat(cfg.PropagationFormat)
+}
+
+// newPropagatorFromNames creates the composite of the propagators named (as in OTEL_PROPAGATORS):
+//
+//	tracecontext  W3C TraceContext
+//	baggage       W3C Baggage
+//	b3            B3 single header (b3)
+//	b3multi       B3 multiple headers (x-b3-*)
+//	jaeger        Jaeger (uber-trace-id)
+func newPropagatorFromNames(names []string) (propagation.TextMapPropagator, error) {
+	propagators := make([]propagation.TextMapPropagator, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case "tracecontext":
+			propagators = append(propagators, propagation.TraceContext{})
+		case "baggage":
+			propagators = append(propagators, propagation.Baggage{})
+		case "b3":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case "b3multi":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		case "jaeger":
+			propagators = append(propagators, jaeger.Jaeger{})
+		default:
+			return nil, fmt.Errorf("unsupported propagator: %q", name)
+		}
+	}
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
+
+// newPropagatorFromFormat creates the propagator for format(s).
+// W3C Baggage is always propagated alongside the trace context.
+func newPropagatorFromFormat(format PropagationFormat) (propagation.TextMapPropagator, error) {
+	if format == 0 {
+		format = PropagationW3C
+	}
+	if format&^propagationAll != 0 {
+		return nil, fmt.Errorf("unsupported propagation format: %d", format)
+	}
+
+	var propagators []propagation.TextMapPropagator
+	if format&PropagationW3C != 0 {
+		propagators = append(propagators, propagation.TraceContext{})
+	}
+	var b3Encoding b3.Encoding
+	if format&PropagationB3Single != 0 {
+		b3Encoding |= b3.B3SingleHeader
+	}
+	if format&PropagationB3Multi != 0 {
+		b3Encoding |= b3.B3MultipleHeader
+	}
+	if b3Encoding != 0 {
+		propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3Encoding)))
+	}
+	propagators = append(propagators, propagation.Baggage{})
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
+
+// Inject injects the trace context (and baggage) of ctx into carrier using the manager's Propagator.
+// Eg: on the client side, m.Inject(ctx, propagation.HeaderCarrier(req.Header)) before sending req
+func (m *Manager) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	m.Propagator.Inject(ctx, carrier)
+}
+
+// Extract returns a copy of ctx containing the remote trace context (and baggage) read from carrier using the
+// manager's Propagator. Spans started from the returned ctx are children of the remote span.
+// Eg: on the server side, ctx := m.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+func (m *Manager) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return m.Propagator.Extract(ctx, carrier)
+}
@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestB3RoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        Config
+		wantHeader string
+	}{
+		{name: "b3", cfg: Config{Propagators: []string{"b3"}}, wantHeader: "B3"},
+		{name: "b3multi", cfg: Config{Propagators: []string{"b3multi"}}, wantHeader: "X-B3-Traceid"},
+		{name: "PropagationB3Single", cfg: Config{PropagationFormat: PropagationB3Single}, wantHeader: "B3"},
+		{name: "PropagationB3Multi", cfg: Config{PropagationFormat: PropagationB3Multi}, wantHeader: "X-B3-Traceid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tt.cfg.Logger = NopLogger
+			m, err := NewInMemory(ctx, tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer m.Shutdown(ctx)
+
+			spanCtx, span := m.Start(ctx, "client")
+			defer span.End()
+			header := http.Header{}
+			m.Inject(spanCtx, propagation.HeaderCarrier(header))
+			if header.Get(tt.wantHeader) == "" {
+				t.Fatalf("%s header not injected, got headers %v", tt.wantHeader, header)
+			}
+			if header.Get("Traceparent") != "" {
+				t.Errorf("traceparent header injected, want B3 headers only")
+			}
+
+			remote := trace.SpanContextFromContext(m.Extract(ctx, propagation.HeaderCarrier(header)))
+			want := span.SpanContext()
+			if remote.TraceID() != want.TraceID() || remote.SpanID() != want.SpanID() {
+				t.Errorf("extracted trace %s span %s, want trace %s span %s", remote.TraceID(), remote.SpanID(), want.TraceID(), want.SpanID())
+			}
+			if !remote.IsRemote() || !remote.IsSampled() {
+				t.Errorf("extracted span context remote=%t sampled=%t, want a remote sampled span context", remote.IsRemote(), remote.IsSampled())
+			}
+		})
+	}
+}
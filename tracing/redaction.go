@@ -0,0 +1,27 @@
+package tracing
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// NewRedactionProcessor returns a span processor handing over the spans that end to next, with the attributes whose key
+// is in keys (Eg: "db.statement" containing SQL with credentials, "http.request.header.authorization") replaced by
+// replacement, or removed if replacement is "". The original spans are left untouched, i.e. other processors see the
+// original values. Eg: use it as one of Config.SpanProcessors, wrapping a processor exporting spans:
+//
+//	NewRedactionProcessor([]string{"db.statement"}, "[REDACTED]", sdktrace.NewBatchSpanProcessor(exporter))
+func NewRedactionProcessor(keys []string, replacement string, next sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+	redacted := make(map[attribute.Key]struct{}, len(keys))
+	for _, key := range keys {
+		redacted[attribute.Key(key)] = struct{}{}
+	}
+	return newTransformProcessor(next, func(span sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+		return mapAttributes(span, func(attr attribute.KeyValue) (attribute.KeyValue, bool, bool) {
+			if _, ok := redacted[attr.Key]; !ok {
+				return attr, false, true
+			}
+			return attribute.String(string(attr.Key), replacement), true, replacement != ""
+		})
+	})
+}
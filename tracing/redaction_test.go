@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRedactionProcessor(t *testing.T) {
+	tests := []struct {
+		name        string
+		replacement string
+		want        []attribute.KeyValue
+	}{
+		{
+			name:        "masked",
+			replacement: "[REDACTED]",
+			want:        []attribute.KeyValue{attribute.String("user", "alice"), attribute.String("password", "[REDACTED]")},
+		},
+		{
+			name: "removed",
+			want: []attribute.KeyValue{attribute.String("user", "alice")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			redacted := tracetest.NewInMemoryExporter()
+			processor := NewRedactionProcessor([]string{"password"}, tt.replacement, sdktrace.NewSimpleSpanProcessor(redacted))
+			m, err := NewInMemory(ctx, Config{Logger: NopLogger, SpanProcessors: []sdktrace.SpanProcessor{processor}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer m.Shutdown(ctx)
+
+			original := []attribute.KeyValue{attribute.String("user", "alice"), attribute.String("password", "hunter2")}
+			_, span := m.Start(ctx, "login")
+			span.SetAttributes(original...)
+			span.End()
+
+			spans := redacted.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("got %d redacted spans, want 1", len(spans))
+			}
+			if got := spans[0].Attributes; !sameAttributes(got, tt.want) {
+				t.Errorf("redacted attributes = %v, want %v", got, tt.want)
+			}
+			// The other processors (here, the manager's own one) see the original attributes
+			recorded := m.RecordedSpans(ctx)
+			if len(recorded) != 1 {
+				t.Fatalf("got %d recorded spans, want 1", len(recorded))
+			}
+			if got := recorded[0].Attributes(); !sameAttributes(got, original) {
+				t.Errorf("original attributes = %v, want %v", got, original)
+			}
+		})
+	}
+}
+
+// sameAttributes returns whether a & b contain the same attributes, in any order.
+func sameAttributes(a, b []attribute.KeyValue) bool {
+	setA, setB := attribute.NewSet(a...), attribute.NewSet(b...)
+	return setA.Equals(&setB)
+}
@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// retryExporter retries failed exports of an exporter (Eg: Zipkin, Jaeger, or OTLP with its native retries disabled)
+// using exponential backoff with jitter, as described by config.
+type retryExporter struct {
+	sdktrace.SpanExporter
+	config RetryConfig
+	logger Logger
+}
+
+// newRetryExporter wraps exporter so that failed exports are retried as described by config.
+// Exports given up on are logged to logger.
+func newRetryExporter(exporter sdktrace.SpanExporter, config RetryConfig, logger Logger) sdktrace.SpanExporter {
+	return &retryExporter{SpanExporter: exporter, config: config.withDefaults(), logger: logger}
+}
+
+func (e *retryExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	start := time.Now()
+	interval := e.config.InitialInterval
+	for attempt := 1; ; attempt++ {
+		err := e.SpanExporter.ExportSpans(ctx, spans)
+		if err == nil {
+			return nil
+		}
+
+		// Randomize the interval by ±50% so that clients don't retry in lockstep (Eg: after a collector restart)
+		wait := interval/2 + time.Duration(rand.Int63n(int64(interval)))
+		if (e.config.MaxAttempts > 0 && attempt >= e.config.MaxAttempts) || time.Since(start)+wait > e.config.MaxElapsedTime {
+			e.logger.Errorf("Could not export %d spans after %d attempt(s), dropping them: %s", len(spans), attempt, err)
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			e.logger.Errorf("Could not export %d spans after %d attempt(s), dropping them: %s", len(spans), attempt, err)
+			return err
+		case <-time.After(wait):
+		}
+
+		interval *= 2
+		if interval > e.config.MaxInterval {
+			interval = e.config.MaxInterval
+		}
+	}
+}
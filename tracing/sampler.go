@@ -0,0 +1,312 @@
+package tracing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SamplingPolicy - a ParentBased sampling configuration with explicit samplers depending on the span's parent,
+// Eg: sample every root span but only 10% of the spans with a remote parent:
+//
+//	&SamplingPolicy{RemoteParentSampled: sdktrace.TraceIDRatioBased(0.1)}
+//
+// Nil samplers default to the sdktrace.ParentBased defaults (i.e. follow the parent's decision).
+type SamplingPolicy struct {
+	// Sampler for spans without a parent (root spans).
+	// If nil, defaults to sdktrace.TraceIDRatioBased(Config.SampleRatio) if set, or sdktrace.AlwaysSample() otherwise.
+	Root sdktrace.Sampler
+
+	// Sampler for spans whose parent is remote (Eg: extracted from request headers) and sampled.
+	// If nil, defaults to sdktrace.AlwaysSample()
+	RemoteParentSampled sdktrace.Sampler
+
+	// Sampler for spans whose parent is remote and not sampled.
+	// If nil, defaults to sdktrace.NeverSample()
+	RemoteParentNotSampled sdktrace.Sampler
+
+	// Sampler for spans whose parent is local (i.e. in the same process) and sampled.
+	// If nil, defaults to sdktrace.AlwaysSample()
+	LocalParentSampled sdktrace.Sampler
+
+	// Sampler for spans whose parent is local and not sampled.
+	// If nil, defaults to sdktrace.NeverSample()
+	LocalParentNotSampled sdktrace.Sampler
+}
+
+// sampler returns the sdktrace.ParentBased sampler described by p, using rootRatio (if non-zero) for root spans
+// unless p.Root is set.
+func (p SamplingPolicy) sampler(rootRatio float64) sdktrace.Sampler {
+	root := p.Root
+	if root == nil {
+		root = sdktrace.AlwaysSample()
+		if rootRatio != 0 {
+			root = sdktrace.TraceIDRatioBased(rootRatio)
+		}
+	}
+	var options []sdktrace.ParentBasedSamplerOption
+	if p.RemoteParentSampled != nil {
+		options = append(options, sdktrace.WithRemoteParentSampled(p.RemoteParentSampled))
+	}
+	if p.RemoteParentNotSampled != nil {
+		options = append(options, sdktrace.WithRemoteParentNotSampled(p.RemoteParentNotSampled))
+	}
+	if p.LocalParentSampled != nil {
+		options = append(options, sdktrace.WithLocalParentSampled(p.LocalParentSampled))
+	}
+	if p.LocalParentNotSampled != nil {
+		options = append(options, sdktrace.WithLocalParentNotSampled(p.LocalParentNotSampled))
+	}
+	return sdktrace.ParentBased(root, options...)
+}
+
+// newSampler returns the sampler described by cfg, wrapping base (see baseSampler): SpansPerSecond caps the root
+// spans it samples, and AlwaysSampleErrors records the spans dropped.
+// The sampling settings are checked by Config.Validate.
+func newSampler(cfg Config, base sdktrace.Sampler) sdktrace.Sampler {
+	sampler := base
+	if cfg.SpansPerSecond > 0 {
+		sampler = newRateLimitedSampler(sampler, cfg.SpansPerSecond)
+	}
+	if _, ok := sampler.(*errorSampler); cfg.sampleErrors() && !ok {
+		sampler = AlwaysSampleOnError(sampler)
+	}
+	return sampler
+}
+
+// baseSampler returns the sampler described by cfg, before rate limiting.
+// SamplingPolicy (using SampleRatio for root spans) wins over SampleRatio or Sampler, which win over DefaultSampler.
+func baseSampler(cfg Config) sdktrace.Sampler {
+	switch {
+	case cfg.SamplingPolicy != nil:
+		if cfg.Sampler != nil {
+			cfg.logger().Warnf("Both Sampler and SamplingPolicy are configured, ignoring Sampler")
+		}
+		return cfg.SamplingPolicy.sampler(cfg.SampleRatio)
+	case cfg.SampleRatio != 0:
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))
+	case cfg.Sampler != nil:
+		return cfg.Sampler
+	default:
+		return DefaultSampler
+	}
+}
+
+// RateLimitedSampler returns a sampler sampling at most spansPerSecond root spans (i.e. traces) per second, regardless
+// of the traffic, Eg: to bound the span volume billed by the backend, which TraceIDRatioBased doesn't.
+// It uses a token bucket allowing bursts of up to spansPerSecond traces (or 1 trace, if spansPerSecond < 1).
+// A spansPerSecond of 0 samples no root span at all; negative values are rejected by Config.Validate.
+// Child spans follow their parent's decision, so that sampled traces are complete.
+// Eg: Config{Sampler: RateLimitedSampler(100)}, or Config.SpansPerSecond to rate limit another sampler.
+func RateLimitedSampler(spansPerSecond float64) sdktrace.Sampler {
+	return newRateLimitedSampler(sdktrace.ParentBased(sdktrace.AlwaysSample()), spansPerSecond)
+}
+
+// rateLimitedSampler gates the root spans sampled by base using a token bucket refilled at a constant rate.
+// Child spans don't take tokens, since dropping a child span of a sampled trace would break the trace: they follow
+// the decision of base, except that the children of a local span not sampled (Eg: a root span dropped by the bucket)
+// are dropped, even if base samples them (Eg: sdktrace.AlwaysSample()).
+type rateLimitedSampler struct {
+	base sdktrace.Sampler
+	rate float64 // tokens (root spans) per second
+
+	mu       sync.Mutex
+	tokens   float64
+	capacity float64
+	last     time.Time
+}
+
+func newRateLimitedSampler(base sdktrace.Sampler, spansPerSecond float64) *rateLimitedSampler {
+	capacity := spansPerSecond
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &rateLimitedSampler{
+		base:     base,
+		rate:     spansPerSecond,
+		tokens:   capacity,
+		capacity: capacity,
+		last:     time.Now(),
+	}
+}
+
+func (s *rateLimitedSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.base.ShouldSample(p)
+	if result.Decision != sdktrace.RecordAndSample {
+		return result
+	}
+	parent := trace.SpanContextFromContext(p.ParentContext)
+	switch {
+	case !parent.IsValid():
+		// Root span: takes a token
+		if s.take() {
+			return result
+		}
+	case !parent.IsRemote() && !parent.IsSampled():
+		// Child of a local span not sampled (Eg: a root span dropped by the bucket), even if base samples it
+	default:
+		return result
+	}
+	return sdktrace.SamplingResult{
+		Decision:   sdktrace.Drop,
+		Tracestate: parent.TraceState(),
+	}
+}
+
+// take takes a token from the bucket, returning false if none is available.
+func (s *rateLimitedSampler) take() bool {
+	if s.rate <= 0 {
+		// The bucket's capacity of at least 1 token would otherwise let the first root span through
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.tokens += now.Sub(s.last).Seconds() * s.rate
+	if s.tokens > s.capacity {
+		s.tokens = s.capacity
+	}
+	s.last = now
+
+	if s.tokens < 1 {
+		return false
+	}
+	s.tokens--
+	return true
+}
+
+func (s *rateLimitedSampler) Description() string {
+	return fmt.Sprintf("RateLimitedSampler{%s,%g}", s.base.Description(), s.rate)
+}
+
+// Sampler returns the sampler configured by Config: Config.Sampler if set, or the sampler built from SamplingPolicy,
+// SampleRatio or DefaultSampler otherwise. It is the sampler of the manager's TracerProvider, without the wrappers
+// added by New (Eg: for SpansPerSecond, AlwaysSampleErrors), which still apply to its decisions.
+// Eg: if s, ok := m.Sampler().(*AdjustableSampler); ok { s.SetRatio(1) }
+func (m *Manager) Sampler() sdktrace.Sampler {
+	return m.sampler
+}
+
+// AdjustableSampler samples a ratio of root spans that can be changed at runtime (Eg: dialed up during an incident,
+// without a redeploy), child spans following their parent's decision (as with Config.SampleRatio).
+// Use it as Config.Sampler, and keep a reference to it (or see Manager.Sampler) to call SetRatio after New.
+// It is safe for concurrent use; a new ratio applies to subsequent sampling decisions.
+type AdjustableSampler struct {
+	state atomic.Pointer[adjustableSamplerState]
+}
+
+// adjustableSamplerState is the ratio of an AdjustableSampler and the corresponding sampler, swapped together.
+type adjustableSamplerState struct {
+	ratio   float64
+	sampler sdktrace.Sampler
+}
+
+var _ sdktrace.Sampler = (*AdjustableSampler)(nil)
+
+// NewAdjustableSampler returns an AdjustableSampler initially sampling ratio (between 0 and 1) of the root spans.
+func NewAdjustableSampler(ratio float64) *AdjustableSampler {
+	s := &AdjustableSampler{}
+	s.SetRatio(ratio)
+	return s
+}
+
+// SetRatio sets the ratio (between 0 and 1) of root spans to sample. Out of range ratios are clamped to [0, 1].
+func (s *AdjustableSampler) SetRatio(ratio float64) {
+	if ratio < 0 {
+		ratio = 0
+	} else if ratio > 1 {
+		ratio = 1
+	}
+	s.state.Store(&adjustableSamplerState{
+		ratio:   ratio,
+		sampler: sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio)),
+	})
+}
+
+// Ratio returns the ratio of root spans currently sampled.
+func (s *AdjustableSampler) Ratio() float64 {
+	return s.state.Load().ratio
+}
+
+func (s *AdjustableSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return s.state.Load().sampler.ShouldSample(p)
+}
+
+func (s *AdjustableSampler) Description() string {
+	return fmt.Sprintf("AdjustableSampler{%g}", s.Ratio())
+}
+
+// SamplingRule - a sampler applying to the spans whose name matches a pattern, see NewRuleBasedSampler.
+type SamplingRule struct {
+	// Glob pattern matched against the whole span name: "*" matches any sequence of characters (including "/")
+	// and "?" any single character. Eg: "GET /health*"
+	SpanNamePattern string
+
+	// Sampler for the spans matching SpanNamePattern.
+	// If nil, defaults to sdktrace.NeverSample() (Eg: to exclude health checks)
+	Sampler sdktrace.Sampler
+}
+
+// NewRuleBasedSampler returns a sampler using the sampler of the first rule (in order) whose pattern matches the span
+// name, or fallback if none does (DefaultSampler if nil), Eg: to exclude health-check spans from sampling:
+//
+//	NewRuleBasedSampler([]SamplingRule{{SpanNamePattern: "GET /healthz", Sampler: sdktrace.NeverSample()}}, nil)
+//
+// Note: rules apply to child spans as well; wrap rule samplers with sdktrace.ParentBased to follow the parent's decision.
+func NewRuleBasedSampler(rules []SamplingRule, fallback sdktrace.Sampler) sdktrace.Sampler {
+	if fallback == nil {
+		fallback = DefaultSampler
+	}
+	s := &ruleBasedSampler{fallback: fallback}
+	for _, rule := range rules {
+		if rule.Sampler == nil {
+			rule.Sampler = sdktrace.NeverSample()
+		}
+		s.rules = append(s.rules, compiledSamplingRule{SamplingRule: rule, pattern: globToRegexp(rule.SpanNamePattern)})
+	}
+	return s
+}
+
+// compiledSamplingRule is a SamplingRule with its glob pattern compiled.
+type compiledSamplingRule struct {
+	SamplingRule
+	pattern *regexp.Regexp
+}
+
+// ruleBasedSampler uses the sampler of the first rule matching the span name, or fallback.
+type ruleBasedSampler struct {
+	rules    []compiledSamplingRule
+	fallback sdktrace.Sampler
+}
+
+func (s *ruleBasedSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, rule := range s.rules {
+		if rule.pattern.MatchString(p.Name) {
+			return rule.Sampler.ShouldSample(p)
+		}
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+func (s *ruleBasedSampler) Description() string {
+	rules := make([]string, len(s.rules))
+	for i, rule := range s.rules {
+		rules[i] = fmt.Sprintf("%q:%s", rule.SpanNamePattern, rule.Sampler.Description())
+	}
+	return fmt.Sprintf("RuleBasedSampler{[%s],%s}", strings.Join(rules, ","), s.fallback.Description())
+}
+
+// globToRegexp compiles the glob pattern (see SamplingRule.SpanNamePattern) into a regular expression matching whole names.
+func globToRegexp(pattern string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, `.*`)
+	expr = strings.ReplaceAll(expr, `\?`, `.`)
+	return regexp.MustCompile(`^` + expr + `$`)
+}
@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSpansPerSecondKeepsChildSpans(t *testing.T) {
+	ctx := context.Background()
+	m, err := NewInMemory(ctx, Config{Logger: NopLogger, SpansPerSecond: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Shutdown(ctx)
+
+	// The bucket holds a single token: the first trace is sampled (root span & child span), the second one isn't
+	for i := 0; i < 2; i++ {
+		rootCtx, root := m.Start(ctx, "root")
+		_, child := m.Start(rootCtx, "child")
+		child.End()
+		root.End()
+	}
+
+	spans := m.RecordedSpans(ctx)
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2 (child & root of the first trace)", len(spans))
+	}
+	if spans[0].Name() != "child" || spans[1].Name() != "root" {
+		t.Errorf("got spans %q, %q, want child, root", spans[0].Name(), spans[1].Name())
+	}
+	if spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Errorf("child span's parent is %s, want root span %s", spans[0].Parent().SpanID(), spans[1].SpanContext().SpanID())
+	}
+}
+
+func TestRateLimitedSamplerCapsRootSpans(t *testing.T) {
+	ctx := context.Background()
+	const perSecond = 10
+	m, err := NewInMemory(ctx, Config{Logger: NopLogger, Sampler: RateLimitedSampler(perSecond)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Shutdown(ctx)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				_, span := m.Start(ctx, "root")
+				span.End()
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	// A full bucket (perSecond tokens to start with), plus the tokens refilled meanwhile
+	maxSampled := perSecond + int(elapsed.Seconds()*perSecond) + 1
+	if sampled := len(m.RecordedSpans(ctx)); sampled < perSecond || sampled > maxSampled {
+		t.Errorf("sampled %d of 4000 root spans in %s, want between %d and %d", sampled, elapsed, perSecond, maxSampled)
+	}
+}
+
+func TestManagerSamplerReturnsConfiguredSampler(t *testing.T) {
+	ctx := context.Background()
+	sampler := NewAdjustableSampler(0)
+	m, err := NewInMemory(ctx, Config{Logger: NopLogger, Sampler: sampler, SpansPerSecond: 100, AlwaysSampleErrors: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Shutdown(ctx)
+
+	adjustable, ok := m.Sampler().(*AdjustableSampler)
+	if !ok || adjustable != sampler {
+		t.Fatalf("Sampler() = %s, want the configured AdjustableSampler", m.Sampler().Description())
+	}
+	adjustable.SetRatio(1)
+	_, span := m.Start(ctx, "operation")
+	span.End()
+	if spans := m.RecordedSpans(ctx); len(spans) != 1 {
+		t.Errorf("got %d spans after SetRatio(1), want 1", len(spans))
+	}
+}
+
+func TestValidateRejectsSamplerWithSampleRatio(t *testing.T) {
+	if err := (Config{Sampler: NewAdjustableSampler(0.5), SampleRatio: 0.1}).Validate(); err == nil {
+		t.Error("Validate() = nil, want an error for Sampler with SampleRatio")
+	}
+}
+
+func TestRateLimitedSamplerZeroRateSamplesNoRootSpan(t *testing.T) {
+	ctx := context.Background()
+	m, err := NewInMemory(ctx, Config{Logger: NopLogger, Sampler: RateLimitedSampler(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Shutdown(ctx)
+
+	_, span := m.Start(ctx, "root")
+	span.End()
+	if spans := m.RecordedSpans(ctx); len(spans) != 0 {
+		t.Errorf("got %d spans, want 0", len(spans))
+	}
+	if err := (Config{Sampler: RateLimitedSampler(-1)}).Validate(); err == nil {
+		t.Error("Validate() = nil, want an error for a negative RateLimitedSampler rate")
+	}
+}
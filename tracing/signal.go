@@ -0,0 +1,50 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// ShutdownOnSignal installs a signal handler calling Shutdown (bounded by DefaultShutdownTimeout) when one of sigs
+// arrives, so that the buffered spans are flushed when the service is stopped. If sigs is empty, it defaults to
+// SIGINT & SIGTERM. Failures to shut down are logged.
+// Once the manager is shut down, the handler is uninstalled and the signal is re-raised, so that the process
+// handles it as it would have without the handler (Eg: exits, by default).
+// The returned function uninstalls the handler (if the signal didn't arrive yet).
+// Eg: defer m.ShutdownOnSignal()()
+func (m *Manager) ShutdownOnSignal(sigs ...os.Signal) (cancel func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, sigs...)
+
+	done := make(chan struct{})
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+
+	go func() {
+		select {
+		case sig := <-signals:
+			ctx, cancelShutdown := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+			defer cancelShutdown()
+			if err := m.Shutdown(ctx); err != nil {
+				m.log().Errorf("Could not shutdown tracing on signal %s: %s", sig, err)
+			}
+			cancel()
+			if p, err := os.FindProcess(os.Getpid()); err == nil {
+				_ = p.Signal(sig)
+			}
+		case <-done:
+		}
+	}()
+	return cancel
+}
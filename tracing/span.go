@@ -0,0 +1,159 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName - the instrumentation scope name of the tracer used by Manager.Start.
+const TracerName = "github.com/ABHINAV-SUREKA/gotracing/tracing"
+
+// Tracer returns a tracer with the given (instrumentation scope) name using the manager's own TracerProvider,
+// so that the manager is usable without otel.SetTracerProvider/otel.GetTracerProvider (Eg: in tests running in parallel).
+// Tracers are tagged with Config.InstrumentationVersion, if set, unless opts contain another version.
+// Eg: m.Tracer("my-service", trace.WithInstrumentationVersion("1.2.3"))
+func (m *Manager) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	if m.instrumentationVersion != "" {
+		opts = append([]trace.TracerOption{trace.WithInstrumentationVersion(m.instrumentationVersion)}, opts...)
+	}
+	return m.TracerProvider.Tracer(name, opts...)
+}
+
+// Start starts a span named spanName (child of the span in ctx, if any) using the manager's tracer named TracerName.
+// It returns the span and a copy of ctx containing it; the caller must End() the span.
+func (m *Manager) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return m.Tracer(TracerName).Start(ctx, spanName, opts...)
+}
+
+// StartSpan starts a span named operationName (child of the span in ctx, if any) using a tracer named after the
+// service (i.e. the "service.name" resource attribute, or TracerName if unknown).
+// It returns the span and a copy of ctx containing it; the caller must End() the span.
+func (m *Manager) StartSpan(ctx context.Context, operationName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	name := m.serviceName
+	if name == "" {
+		name = TracerName
+	}
+	return m.Tracer(name).Start(ctx, operationName, opts...)
+}
+
+// Span starts a span named name (as Start) and returns a function ending it, which records the error pointed to by err
+// (see RecordError) if any, so that the span's status reflects the outcome of the function without extra code:
+//
+//	func (s *Service) Process(ctx context.Context) (err error) {
+//		ctx, end := s.tracing.Span(ctx, "Process")
+//		defer end(&err)
+//		...
+//	}
+//
+// end(nil) just ends the span.
+func (m *Manager) Span(ctx context.Context, name string) (context.Context, func(err *error)) {
+	ctx, span := m.Start(ctx, name)
+	return ctx, func(err *error) {
+		if err != nil {
+			m.RecordError(span, *err)
+		}
+		span.End()
+	}
+}
+
+// SpanFromContext returns the current span in ctx, or a no-op span if there is none.
+func (m *Manager) SpanFromContext(ctx context.Context) trace.Span {
+	return trace.SpanFromContext(ctx)
+}
+
+// SpanContextFromContext returns the span context (Eg: trace & span IDs) of the current span in ctx,
+// or an empty (invalid) span context if there is none.
+func (m *Manager) SpanContextFromContext(ctx context.Context) trace.SpanContext {
+	return trace.SpanContextFromContext(ctx)
+}
+
+// RecordError records err on span as an exception event (with "exception.escaped" set, i.e. err is returned past the
+// span's scope) and marks the span as failed, with err's message as the status description.
+// It replaces the span.RecordError(err) & span.SetStatus(codes.Error, err.Error()) pair. A nil err is ignored.
+func (m *Manager) RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err, trace.WithAttributes(semconv.ExceptionEscaped(true)))
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// RecordErrorf is RecordError with an error formatted according to format (as fmt.Errorf, i.e. %w wraps an error).
+// Eg: m.RecordErrorf(span, "could not fetch user %d: %w", id, err)
+func (m *Manager) RecordErrorf(span trace.Span, format string, args ...interface{}) {
+	m.RecordError(span, fmt.Errorf(format, args...))
+}
+
+// SetSpanAttributes sets attrs on span, converting each value to the attribute type of the same kind
+// (Eg: int -> attribute.Int64, []string -> attribute.StringSlice), so that callers needn't build attribute.KeyValue values.
+// Values of other types (Eg: structs) are set as their fmt.Sprintf("%v") string, with a warning.
+// Eg: m.SetSpanAttributes(span, map[string]interface{}{"user.id": 42, "cache.hit": true})
+func (m *Manager) SetSpanAttributes(span trace.Span, attrs map[string]interface{}) {
+	span.SetAttributes(m.attributes(attrs)...)
+}
+
+// AddEvent adds an event named name with attrs (converted as by SetSpanAttributes) to the current span in ctx.
+// Eg: m.AddEvent(ctx, "cache.miss", map[string]interface{}{"cache.key": key})
+func (m *Manager) AddEvent(ctx context.Context, name string, attrs map[string]interface{}) {
+	trace.SpanFromContext(ctx).AddEvent(name, trace.WithAttributes(m.attributes(attrs)...))
+}
+
+// attributes returns attrs as attributes (see SetSpanAttributes).
+func (m *Manager) attributes(attrs map[string]interface{}) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for k, v := range attrs {
+		kvs = append(kvs, m.attribute(k, v))
+	}
+	return kvs
+}
+
+// attribute returns the attribute k with value v (see SetSpanAttributes).
+func (m *Manager) attribute(k string, v interface{}) attribute.KeyValue {
+	switch v := v.(type) {
+	case string:
+		return attribute.String(k, v)
+	case bool:
+		return attribute.Bool(k, v)
+	case int:
+		return attribute.Int(k, v)
+	case int8:
+		return attribute.Int64(k, int64(v))
+	case int16:
+		return attribute.Int64(k, int64(v))
+	case int32:
+		return attribute.Int64(k, int64(v))
+	case int64:
+		return attribute.Int64(k, v)
+	case uint8:
+		return attribute.Int64(k, int64(v))
+	case uint16:
+		return attribute.Int64(k, int64(v))
+	case uint32:
+		return attribute.Int64(k, int64(v))
+	case float32:
+		return attribute.Float64(k, float64(v))
+	case float64:
+		return attribute.Float64(k, v)
+	case []string:
+		return attribute.StringSlice(k, v)
+	case []bool:
+		return attribute.BoolSlice(k, v)
+	case []int:
+		return attribute.IntSlice(k, v)
+	case []int64:
+		return attribute.Int64Slice(k, v)
+	case []float64:
+		return attribute.Float64Slice(k, v)
+	case fmt.Stringer:
+		return attribute.Stringer(k, v)
+	default:
+		// Note: uint, uint64 & uintptr values may not fit in an int64
+		m.log().Warnf("Unsupported type %T for span attribute %q, setting it as a string", v, k)
+		return attribute.String(k, fmt.Sprintf("%v", v))
+	}
+}
@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestStartSpan(t *testing.T) {
+	ctx := context.Background()
+	m, err := NewInMemory(ctx, Config{Logger: NopLogger, Attributes: map[string]string{"service.name": "checkout"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Shutdown(ctx)
+
+	spanCtx, span := m.StartSpan(ctx, "ProcessOrder")
+	span.End()
+
+	if got := m.SpanFromContext(spanCtx); got != span {
+		t.Errorf("returned context doesn't contain the returned span")
+	}
+	spans := m.RecordedSpans(ctx)
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if spans[0].Name() != "ProcessOrder" {
+		t.Errorf("span name = %q, want ProcessOrder", spans[0].Name())
+	}
+	// The tracer is named after the service
+	if scope := spans[0].InstrumentationScope().Name; scope != "checkout" {
+		t.Errorf("tracer name = %q, want checkout", scope)
+	}
+}
+
+func TestSpan(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name            string
+		err             error
+		nilErr          bool // end(nil) instead of end(&err)
+		wantStatus      codes.Code
+		wantDescription string
+		wantException   bool
+	}{
+		{name: "error", err: errFailed, wantStatus: codes.Error, wantDescription: "failed", wantException: true},
+		{name: "success", wantStatus: codes.Unset},
+		{name: "nil error pointer", nilErr: true, wantStatus: codes.Unset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			m, err := NewInMemory(ctx, Config{Logger: NopLogger})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer m.Shutdown(ctx)
+
+			func() (err error) {
+				_, end := m.Span(ctx, "operation")
+				if tt.nilErr {
+					defer end(nil)
+				} else {
+					defer end(&err)
+				}
+				return tt.err
+			}()
+
+			spans := m.RecordedSpans(ctx)
+			if len(spans) != 1 {
+				t.Fatalf("got %d ended spans, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.Name() != "operation" {
+				t.Errorf("span name = %q, want operation", span.Name())
+			}
+			if status := span.Status(); status.Code != tt.wantStatus || status.Description != tt.wantDescription {
+				t.Errorf("span status = %s %q, want %s %q", status.Code, status.Description, tt.wantStatus, tt.wantDescription)
+			}
+			events := span.Events()
+			if !tt.wantException {
+				if len(events) != 0 {
+					t.Errorf("got %d span events, want none", len(events))
+				}
+				return
+			}
+			if len(events) != 1 || events[0].Name != semconv.ExceptionEventName {
+				t.Fatalf("got span events %v, want a single exception event", events)
+			}
+			attrs := attribute.NewSet(events[0].Attributes...)
+			if message, _ := attrs.Value(semconv.ExceptionMessageKey); message.AsString() != "failed" {
+				t.Errorf("exception.message = %q, want failed", message.AsString())
+			}
+			if escaped, _ := attrs.Value(semconv.ExceptionEscapedKey); !escaped.AsBool() {
+				t.Errorf("exception.escaped = %v, want true", escaped.AsBool())
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Names of the counters recorded by the processor returned by NewMetricsProcessor, and of their attributes.
+const (
+	SpansStartedMetric = "tracing.spans.started"
+	SpansEndedMetric   = "tracing.spans.ended"
+
+	SpanNameMetricAttribute   = "span.name"
+	SpanStatusMetricAttribute = "span.status" // Eg: "Unset", "Error", "Ok"
+)
+
+// NewMetricsProcessor returns a span processor counting the spans started (SpansStartedMetric, by span name) and ended
+// (SpansEndedMetric, by span name & status code) using meter, before handing them over to next, so that trace volume
+// dashboards needn't query the tracing backend. Spans are counted whether they are sampled or not.
+// Errors creating the counters are reported to the otel error handler (see Config.ErrorHandler).
+// Eg: NewMetricsProcessor(otel.Meter("my-service"), sdktrace.NewBatchSpanProcessor(exporter))
+// Note: span names should have a low cardinality (see Config.SpanNameSanitizer), as each name is a separate metric series.
+func NewMetricsProcessor(meter metric.Meter, next sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+	started, err := meter.Int64Counter(SpansStartedMetric, metric.WithDescription("Number of spans started"), metric.WithUnit("{span}"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	ended, err := meter.Int64Counter(SpansEndedMetric, metric.WithDescription("Number of spans ended"), metric.WithUnit("{span}"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	return &metricsProcessor{next: next, started: started, ended: ended}
+}
+
+// metricsProcessor counts the spans handed over to next.
+type metricsProcessor struct {
+	next    sdktrace.SpanProcessor
+	started metric.Int64Counter
+	ended   metric.Int64Counter
+}
+
+func (p *metricsProcessor) OnStart(ctx context.Context, span sdktrace.ReadWriteSpan) {
+	p.started.Add(ctx, 1, metric.WithAttributes(attribute.String(SpanNameMetricAttribute, span.Name())))
+	p.next.OnStart(ctx, span)
+}
+
+func (p *metricsProcessor) OnEnd(span sdktrace.ReadOnlySpan) {
+	// OnEnd isn't given a context
+	p.ended.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String(SpanNameMetricAttribute, span.Name()),
+		attribute.String(SpanStatusMetricAttribute, span.Status().Code.String()),
+	))
+	p.next.OnEnd(span)
+}
+
+func (p *metricsProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *metricsProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// newTLSConfig creates the TLS configuration for (mutual) TLS described by cfg.TLSConfig or the TLS files of cfg.
+// Returns nil if none of them are set, in which case the system's root CAs are used to verify the server.
+func newTLSConfig(cfg ExporterConfig) (*tls.Config, error) {
+	if cfg.TLSConfig == nil && cfg.TLSClientCertFile == "" && cfg.TLSClientKeyFile == "" && cfg.TLSCACertFile == "" {
+		return nil, nil
+	}
+	if cfg.Insecure {
+		return nil, errors.New("TLS configuration can't be combined with Insecure")
+	}
+	// An in-memory TLS configuration takes precedence over the files
+	if cfg.TLSConfig != nil {
+		return cfg.TLSConfig, nil
+	}
+	if (cfg.TLSClientCertFile == "") != (cfg.TLSClientKeyFile == "") {
+		return nil, errors.New("both TLSClientCertFile and TLSClientKeyFile must be configured for client certificate authentication")
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.TLSClientCertFile != "" {
+		clientCert, err := os.ReadFile(cfg.TLSClientCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read client certificate: %s", err)
+		}
+		clientKey, err := os.ReadFile(cfg.TLSClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read client key: %s", err)
+		}
+		certificate, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse client key pair (%s, %s): %s", cfg.TLSClientCertFile, cfg.TLSClientKeyFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	if cfg.TLSCACertFile != "" {
+		caCert, err := os.ReadFile(cfg.TLSCACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CA certificate: %s", err)
+		}
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("could not parse CA certificate %s: no valid PEM certificates found", cfg.TLSCACertFile)
+		}
+		tlsConfig.RootCAs = certPool
+	}
+	return tlsConfig, nil
+}
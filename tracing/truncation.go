@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// truncationSuffix - appended to the truncated attribute values.
+const truncationSuffix = "..."
+
+// NewTruncationProcessor returns a span processor handing over the spans that end to next, with the string (and string
+// slice) attribute values longer than maxLen characters truncated to maxLen characters followed by "...", so that
+// exports aren't rejected by collectors limiting the message size. A maxLen <= 0 disables truncation.
+// Unlike the SDK's SpanLimits.AttributeValueLengthLimit, the original spans are left untouched, i.e. other processors
+// see the original values. Eg: NewTruncationProcessor(1024, sdktrace.NewBatchSpanProcessor(exporter))
+func NewTruncationProcessor(maxLen int, next sdktrace.SpanProcessor) sdktrace.SpanProcessor {
+	if maxLen <= 0 {
+		return next
+	}
+	return newTransformProcessor(next, func(span sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+		return mapAttributes(span, func(attr attribute.KeyValue) (attribute.KeyValue, bool, bool) {
+			truncated, ok := truncateAttribute(attr, maxLen)
+			return truncated, ok, true
+		})
+	})
+}
+
+// truncateAttribute returns attr with its value truncated to maxLen characters, and whether it was truncated.
+func truncateAttribute(attr attribute.KeyValue, maxLen int) (attribute.KeyValue, bool) {
+	switch attr.Value.Type() {
+	case attribute.STRING:
+		if value, ok := truncate(attr.Value.AsString(), maxLen); ok {
+			return attribute.String(string(attr.Key), value), true
+		}
+	case attribute.STRINGSLICE:
+		values := attr.Value.AsStringSlice()
+		truncated := false
+		for i, value := range values {
+			if value, ok := truncate(value, maxLen); ok {
+				values[i] = value
+				truncated = true
+			}
+		}
+		if truncated {
+			return attribute.StringSlice(string(attr.Key), values), true
+		}
+	}
+	return attr, false
+}
+
+// truncate returns s truncated to maxLen characters followed by "...", and whether it was truncated.
+func truncate(s string, maxLen int) (string, bool) {
+	if len(s) <= maxLen {
+		// Fast path: s has at most len(s) characters
+		return s, false
+	}
+	for i := range s {
+		if maxLen == 0 {
+			return s[:i] + truncationSuffix, true
+		}
+		maxLen--
+	}
+	return s, false
+}
@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTruncationProcessor(t *testing.T) {
+	ctx := context.Background()
+	truncated := tracetest.NewInMemoryExporter()
+	processor := NewTruncationProcessor(4, sdktrace.NewSimpleSpanProcessor(truncated))
+	m, err := NewInMemory(ctx, Config{Logger: NopLogger, SpanProcessors: []sdktrace.SpanProcessor{processor}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Shutdown(ctx)
+
+	_, span := m.Start(ctx, "operation")
+	span.SetAttributes(
+		attribute.String("short", "abcd"),
+		attribute.String("long", "héllo wörld"),
+		attribute.StringSlice("slice", []string{"ab", "abcdef"}),
+		attribute.Int("int", 123456),
+	)
+	span.End()
+
+	spans := truncated.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d truncated spans, want 1", len(spans))
+	}
+	// Lengths are in characters, not bytes
+	want := []attribute.KeyValue{
+		attribute.String("short", "abcd"),
+		attribute.String("long", "héll..."),
+		attribute.StringSlice("slice", []string{"ab", "abcd..."}),
+		attribute.Int("int", 123456),
+	}
+	if got := spans[0].Attributes; !sameAttributes(got, want) {
+		t.Errorf("truncated attributes = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,137 @@
+package tracing
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Validate checks that c describes a configuration New accepts, without creating anything (Eg: no connection is
+// opened), so that misconfiguration can be caught early, Eg: in a unit test of the code loading the configuration.
+// It reports every problem found (see errors.Join) among: malformed endpoints, out of range sampling settings,
+// Sampler combined with SampleRatio, inconsistent batching settings, unsupported
+// backends/transports/compressions/propagators, TLS settings combined with Insecure and unreadable (Eg: missing)
+// or invalid TLS files, and logs/metrics enabled with a backend not supporting them.
+// Fields overlapping by design (Eg: Sampler & SamplingPolicy, DebugOutput & Endpoint) aren't errors: the precedence
+// documented on the fields applies, with a warning logged by New.
+// A disabled configuration (see Disabled) is always valid, since New ignores all the other fields.
+// Note: UseEnvFallback isn't applied: New validates the configuration once merged with the environment.
+func (c Config) Validate() error {
+	if c.Disabled {
+		return nil
+	}
+
+	var errs []error
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("invalid sample ratio %v: must be between 0 and 1", c.SampleRatio))
+	}
+	if c.SampleRatio != 0 && c.Sampler != nil && c.SamplingPolicy == nil {
+		errs = append(errs, errors.New("both Sampler and SampleRatio are set: only one of them can be"))
+	}
+	if c.SpansPerSecond < 0 {
+		errs = append(errs, fmt.Errorf("invalid spans per second %v: must not be negative", c.SpansPerSecond))
+	}
+	if s, ok := c.Sampler.(*rateLimitedSampler); ok && s.rate < 0 {
+		errs = append(errs, fmt.Errorf("invalid RateLimitedSampler spans per second %v: must not be negative", s.rate))
+	}
+	if err := c.validateProcessor(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := newPropagator(c); err != nil {
+		errs = append(errs, err)
+	}
+
+	mainExporter := c.exporterConfig()
+	if err := mainExporter.validate(); err != nil {
+		errs = append(errs, err)
+	}
+	for i, exporterCfg := range c.Exporters {
+		if err := exporterCfg.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("exporter #%d: %s", i+1, err))
+		}
+	}
+	if backend := mainExporter.backend(); backend != BackendOTLP && backend != BackendStdout {
+		if c.EnableLogs {
+			errs = append(errs, errors.New("logs can only be exported with BackendOTLP or DebugOutput"))
+		}
+		if c.EnableMetrics {
+			errs = append(errs, errors.New("metrics can only be exported with BackendOTLP or DebugOutput"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateProcessor checks the processor settings of c (see Validate).
+func (c Config) validateProcessor() error {
+	switch mode := c.ProcessorMode; mode {
+	case ProcessorBatch:
+		// Compare against the SDK defaults, for whichever isn't set
+		maxQueueSize, maxExportBatchSize := sdktrace.DefaultMaxQueueSize, sdktrace.DefaultMaxExportBatchSize
+		if c.MaxQueueSize > 0 {
+			maxQueueSize = c.MaxQueueSize
+		}
+		if c.MaxExportBatchSize > 0 {
+			maxExportBatchSize = c.MaxExportBatchSize
+		}
+		if maxExportBatchSize > maxQueueSize {
+			return fmt.Errorf("max export batch size (%d) must not exceed max queue size (%d)", maxExportBatchSize, maxQueueSize)
+		}
+		return nil
+	case ProcessorSimple:
+		return nil
+	default:
+		return fmt.Errorf("unsupported processor mode: %d", mode)
+	}
+}
+
+// validate checks that c describes an exporter newExporter can create (see Config.Validate).
+func (c ExporterConfig) validate() error {
+	if c.Endpoint == "" {
+		c.Endpoint = defaultEndpoint(c)
+	}
+	if c.Compression != "" && c.Compression != CompressionNone && c.Compression != CompressionGzip {
+		return fmt.Errorf("unknown compression %q: must be one of %q, %q", c.Compression, CompressionNone, CompressionGzip)
+	}
+
+	switch backend := c.backend(); backend {
+	case BackendOTLP:
+		if c.Transport != TransportGRPC && c.Transport != TransportHTTP {
+			return fmt.Errorf("unsupported transport: %d", c.Transport)
+		}
+		if c.GRPCConn != nil && c.Transport == TransportGRPC && c.Compression == CompressionGzip {
+			// The compressor is a dial option of the connections this package opens; it can't be added to an existing one
+			return errors.New("can't combine Compression with GRPCConn: configure the compressor on the connection instead")
+		}
+		if c.GRPCConn == nil || c.Transport != TransportGRPC {
+			var err error
+			if c, err = normalizeOTLPEndpoint(c); err != nil {
+				return err
+			}
+		}
+		// Reads (and parses) the TLS files, if any
+		_, err := newTLSConfig(c)
+		return err
+	case BackendZipkin:
+		if u, err := url.Parse(c.Endpoint); err != nil || u.Host == "" {
+			return fmt.Errorf("invalid Zipkin collector URL %q", c.Endpoint)
+		}
+		return nil
+	case BackendJaeger:
+		if _, _, err := net.SplitHostPort(c.Endpoint); err != nil {
+			return fmt.Errorf("invalid Jaeger agent endpoint %q: %s", c.Endpoint, err)
+		}
+		return nil
+	case BackendFile:
+		if c.FileOutput == "" {
+			return errors.New("no file to write traces to: FileOutput must be set with BackendFile")
+		}
+		return nil
+	case BackendStdout, BackendInMemory:
+		return nil
+	default:
+		return fmt.Errorf("unsupported backend: %d", backend)
+	}
+}